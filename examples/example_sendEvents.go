@@ -30,7 +30,7 @@ func ExampleSendEvents() {
 		Event: map[string]interface{}{"message": "Hello, Splunk!"},
 	}
 
-	err = client.SendEvents("test_index", []splunk.Event{event})
+	_, err = client.SendEvents("test_index", []splunk.Event{event})
 	if err != nil {
 		fmt.Println("Error sending event:", err)
 		return
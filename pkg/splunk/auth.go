@@ -0,0 +1,190 @@
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// basicAuthSessionTTL bounds how long BasicAuth trusts a session key
+// before proactively logging in again. Splunk's /services/auth/login
+// response does not report an expiry, so this is a conservative estimate;
+// a 401 triggers an immediate re-login regardless.
+const basicAuthSessionTTL = 30 * time.Minute
+
+// Authenticator attaches credentials to an outbound request, refreshing
+// or re-issuing them against c as needed. Install one with
+// Client.WithAuth; NewClient's username/password/token arguments are
+// equivalent to BasicAuth/BearerToken but are kept as the default for
+// backward compatibility.
+type Authenticator interface {
+	Authenticate(ctx context.Context, c *Client, req *http.Request) error
+}
+
+// reauthenticator is implemented by Authenticators that can discard
+// cached credentials so the next Authenticate call re-issues them. The
+// Client calls Invalidate and retries once when a request comes back 401.
+type reauthenticator interface {
+	Invalidate()
+}
+
+// WithAuth installs a, replacing the BasicAuth/BearerToken behavior
+// implied by the Username/Password/Token fields NewClient set. Callers
+// can swap strategies without changing any call site, since every
+// request except the HEC calls (SendEvents, WaitForAcks), which
+// authenticate with Token, goes through Client's internal auth helpers.
+func (c *Client) WithAuth(a Authenticator) *Client {
+	c.auth = a
+	return c
+}
+
+// BearerToken authenticates every request with a fixed, non-expiring
+// bearer token. This is the Authenticator equivalent of passing a token
+// to NewClient.
+type BearerToken struct {
+	Token string
+}
+
+// Authenticate implements Authenticator.
+func (b BearerToken) Authenticate(_ context.Context, _ *Client, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+b.Token)
+	return nil
+}
+
+// BasicAuth authenticates by exchanging a username and password for a
+// session key via POST /services/auth/login, caching the key until it
+// expires or a request comes back 401. This is the Authenticator
+// equivalent of passing a username and password to NewClient, except it
+// uses Splunk's session-key login rather than HTTP Basic auth on every
+// request.
+type BasicAuth struct {
+	Username string
+	Password string
+
+	mu         sync.Mutex
+	sessionKey string
+	expiresAt  time.Time
+}
+
+// Authenticate implements Authenticator.
+func (b *BasicAuth) Authenticate(ctx context.Context, c *Client, req *http.Request) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.sessionKey == "" || time.Now().After(b.expiresAt) {
+		if err := b.login(ctx, c); err != nil {
+			return err
+		}
+	}
+	req.Header.Set("Authorization", "Splunk "+b.sessionKey)
+	return nil
+}
+
+// Invalidate implements reauthenticator.
+func (b *BasicAuth) Invalidate() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sessionKey = ""
+}
+
+func (b *BasicAuth) login(ctx context.Context, c *Client) error {
+	loginURL := c.BaseURL + "/services/auth/login"
+	values := url.Values{
+		"username":    {b.Username},
+		"password":    {b.Password},
+		"output_mode": {"json"},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", loginURL, strings.NewReader(values.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("basic auth login failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("basic auth login failed: %s", resp.Status)
+	}
+
+	var parsed struct {
+		SessionKey string `json:"sessionKey"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return fmt.Errorf("failed to parse login response: %w", err)
+	}
+	if parsed.SessionKey == "" {
+		return fmt.Errorf("login response did not contain a sessionKey")
+	}
+
+	b.sessionKey = parsed.SessionKey
+	b.expiresAt = time.Now().Add(basicAuthSessionTTL)
+	return nil
+}
+
+// SplunkCloudJWT authenticates with a Splunk Cloud JWT bearer token,
+// calling Refresh to obtain a new token once the cached one is within
+// RefreshBefore of ExpiresAt.
+type SplunkCloudJWT struct {
+	Token         string
+	ExpiresAt     time.Time
+	RefreshBefore time.Duration
+	// Refresh returns a new token and its expiry. Required for automatic
+	// refresh; if nil, Token is used until a 401 forces Invalidate to
+	// clear it, at which point Authenticate returns an error.
+	Refresh func(ctx context.Context) (token string, expiresAt time.Time, err error)
+
+	mu sync.Mutex
+}
+
+// Authenticate implements Authenticator.
+func (j *SplunkCloudJWT) Authenticate(ctx context.Context, _ *Client, req *http.Request) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	needsRefresh := j.Token == "" || time.Now().Add(j.RefreshBefore).After(j.ExpiresAt)
+	if needsRefresh && j.Refresh != nil {
+		token, expiresAt, err := j.Refresh(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to refresh Splunk Cloud JWT: %w", err)
+		}
+		j.Token = token
+		j.ExpiresAt = expiresAt
+	} else if j.Token == "" {
+		return fmt.Errorf("Splunk Cloud JWT is empty and no Refresh function is set")
+	}
+
+	req.Header.Set("Authorization", "Bearer "+j.Token)
+	return nil
+}
+
+// Invalidate implements reauthenticator.
+func (j *SplunkCloudJWT) Invalidate() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Token = ""
+}
+
+// CookieAuth authenticates by attaching session cookies obtained out of
+// band from a SAML/SSO login flow, for deployments where username,
+// password, and token auth are disabled in favor of SSO.
+type CookieAuth struct {
+	Cookies []*http.Cookie
+}
+
+// Authenticate implements Authenticator.
+func (c CookieAuth) Authenticate(_ context.Context, _ *Client, req *http.Request) error {
+	for _, cookie := range c.Cookies {
+		req.AddCookie(cookie)
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package splunk
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultMaxBatchBytes is used when Client.MaxBatchBytes is unset.
+	defaultMaxBatchBytes = 1 << 20 // 1 MiB
+	// defaultMaxBatchEvents is used when Client.MaxBatchEvents is unset.
+	defaultMaxBatchEvents = 500
+)
+
+// BatchError aggregates the per-batch outcome of a SendEvents call. It
+// identifies batches by their index in send order; it does not record
+// which events of the original []Event each batch held.
+type BatchError struct {
+	// Failed maps the index of a failed batch (in send order) to the
+	// error that batch returned.
+	Failed map[int]error
+	// Succeeded lists the indexes of batches that were accepted by
+	// Splunk, in send order.
+	Succeeded []int
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("hec: %d of %d batches failed: %v", len(e.Failed), len(e.Failed)+len(e.Succeeded), e.Failed)
+}
+
+// hecAckResponse is the JSON body Splunk returns from a HEC event POST.
+type hecAckResponse struct {
+	Text  string `json:"text"`
+	Code  int    `json:"code"`
+	AckID int64  `json:"ackId"`
+}
+
+// SendEvents sends events to a Splunk index using the HTTP Event Collector
+// (HEC) API. Events are grouped into batches of newline-delimited JSON
+// payloads (each event on its own line) bounded by Client.MaxBatchBytes and
+// Client.MaxBatchEvents, and each batch is POSTed to
+// /services/collector/event in a single request.
+//
+// If Client.UseAck is true, every batch is sent with the
+// X-Splunk-Request-Channel header and its ackId is recorded; pass the
+// returned ack IDs to WaitForAcks to confirm indexing durability.
+//
+// If one or more batches fail, SendEvents returns a *BatchError listing,
+// by index in send order, which batches succeeded and which failed.
+func (c *Client) SendEvents(indexName string, events []Event) ([]int64, error) {
+	if c.Token == "" {
+		return nil, fmt.Errorf("HEC requires a token for authentication")
+	}
+
+	batches, err := c.buildEventBatches(indexName, events)
+	if err != nil {
+		return nil, err
+	}
+
+	hecURL := strings.TrimRight(c.BaseURL, "/") + "/services/collector/event"
+	var ackIDs []int64
+	batchErr := &BatchError{Failed: map[int]error{}}
+
+	for i, batch := range batches {
+		ackID, err := c.sendBatch(hecURL, batch)
+		if err != nil {
+			batchErr.Failed[i] = err
+			continue
+		}
+		batchErr.Succeeded = append(batchErr.Succeeded, i)
+		if c.UseAck {
+			ackIDs = append(ackIDs, ackID)
+		}
+	}
+
+	if len(batchErr.Failed) > 0 {
+		return ackIDs, batchErr
+	}
+	return ackIDs, nil
+}
+
+// buildEventBatches groups events into newline-delimited JSON payloads no
+// larger than MaxBatchBytes and no longer than MaxBatchEvents.
+func (c *Client) buildEventBatches(indexName string, events []Event) ([][]byte, error) {
+	maxBytes := c.MaxBatchBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBatchBytes
+	}
+	maxEvents := c.MaxBatchEvents
+	if maxEvents <= 0 {
+		maxEvents = defaultMaxBatchEvents
+	}
+
+	var batches [][]byte
+	var buf bytes.Buffer
+	count := 0
+
+	flush := func() {
+		if buf.Len() > 0 {
+			batches = append(batches, append([]byte(nil), buf.Bytes()...))
+			buf.Reset()
+			count = 0
+		}
+	}
+
+	for _, event := range events {
+		payload := map[string]any{
+			"index": indexName,
+			"event": event.Event,
+		}
+		if event.Time != 0 {
+			payload["time"] = event.Time
+		}
+		line, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal event: %w", err)
+		}
+
+		if buf.Len() > 0 && (buf.Len()+len(line) > maxBytes || count >= maxEvents) {
+			flush()
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		count++
+	}
+	flush()
+
+	return batches, nil
+}
+
+// sendBatch POSTs a single newline-delimited JSON batch to the HEC event
+// endpoint and returns the ackId Splunk assigned it, if acknowledgement is
+// enabled.
+func (c *Client) sendBatch(hecURL string, batch []byte) (int64, error) {
+	headers := map[string]string{
+		"Authorization": "Splunk " + c.Token,
+		"Content-Type":  "application/json",
+	}
+	if c.UseAck {
+		headers["X-Splunk-Request-Channel"] = c.requestChannel
+	}
+
+	resp, err := c.doRequest(context.Background(), "POST", hecURL, headers, func() io.Reader { return bytes.NewReader(batch) })
+	if err != nil {
+		return 0, fmt.Errorf("failed to send batch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read batch response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to send batch: %s - %s", resp.Status, string(body))
+	}
+
+	if !c.UseAck {
+		return 0, nil
+	}
+
+	var ack hecAckResponse
+	if err := json.Unmarshal(body, &ack); err != nil {
+		return 0, fmt.Errorf("failed to parse ack response: %w", err)
+	}
+	return ack.AckID, nil
+}
+
+// hecAckRequest is the JSON body posted to /services/collector/ack.
+type hecAckRequest struct {
+	Acks []int64 `json:"acks"`
+}
+
+// hecAckStatus is the JSON body returned by /services/collector/ack.
+type hecAckStatus struct {
+	Acks map[string]bool `json:"acks"`
+}
+
+// WaitForAcks polls /services/collector/ack until every ackID returned by
+// SendEvents reports true, the context is canceled, or its deadline is
+// reached. It requires the client to have been used with UseAck set to
+// true so the indexer associates the ack IDs with this client's request
+// channel.
+func (c *Client) WaitForAcks(ctx context.Context, ackIDs []int64, pollInterval time.Duration) error {
+	if !c.UseAck {
+		return fmt.Errorf("WaitForAcks requires Client.UseAck to be true")
+	}
+	if len(ackIDs) == 0 {
+		return nil
+	}
+
+	pending := make(map[int64]bool, len(ackIDs))
+	for _, id := range ackIDs {
+		pending[id] = true
+	}
+
+	ackURL := strings.TrimRight(c.BaseURL, "/") + "/services/collector/ack"
+
+	for {
+		remaining := make([]int64, 0, len(pending))
+		for id := range pending {
+			remaining = append(remaining, id)
+		}
+
+		reqBody, err := json.Marshal(hecAckRequest{Acks: remaining})
+		if err != nil {
+			return fmt.Errorf("failed to marshal ack request: %w", err)
+		}
+
+		headers := map[string]string{
+			"Authorization":            "Splunk " + c.Token,
+			"Content-Type":             "application/json",
+			"X-Splunk-Request-Channel": c.requestChannel,
+		}
+		resp, err := c.doRequest(ctx, "POST", ackURL, headers, func() io.Reader { return bytes.NewReader(reqBody) })
+		if err != nil {
+			return fmt.Errorf("failed to poll acks: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return fmt.Errorf("failed to poll acks: %s - %s", resp.Status, string(body))
+		}
+
+		var status hecAckStatus
+		err = json.NewDecoder(resp.Body).Decode(&status)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to parse ack status: %w", err)
+		}
+
+		for idStr, acked := range status.Acks {
+			if !acked {
+				continue
+			}
+			var id int64
+			if _, err := fmt.Sscanf(idStr, "%d", &id); err != nil {
+				continue
+			}
+			delete(pending, id)
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// newRequestChannel generates a random UUIDv4 to use as the
+// X-Splunk-Request-Channel header value for HEC acknowledgement.
+func newRequestChannel() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "00000000-0000-4000-8000-000000000000"
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
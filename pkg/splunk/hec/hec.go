@@ -0,0 +1,300 @@
+// Package hec provides a client for Splunk's HTTP Event Collector (HEC),
+// the standard ingestion path for indexing events over HTTP. It is
+// independent of splunk.Client: HEC authenticates with a token minted for
+// a specific token-enabled input, not the management-port credentials
+// splunk.Client uses for search.
+package hec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxBatchBytes  = 1 << 20 // 1 MiB
+	defaultMaxBatchEvents = 500
+	defaultMaxRetries     = 3
+	defaultRetryBase      = 200 * time.Millisecond
+	defaultRetryMax       = 5 * time.Second
+)
+
+// Event is a single event to index via HEC.
+type Event struct {
+	// Time is the event's epoch timestamp, in seconds. Leave at zero to
+	// let Splunk assign the time of indexing.
+	Time float64 `json:"time,omitempty"`
+	// Index, Source, Sourcetype, and Host override the corresponding HEC
+	// input defaults for this event only.
+	Index      string `json:"index,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Sourcetype string `json:"sourcetype,omitempty"`
+	Host       string `json:"host,omitempty"`
+	// Fields sets indexed field extractions alongside the event.
+	Fields map[string]any `json:"fields,omitempty"`
+	// Event is the event payload itself: a string or any JSON-marshalable
+	// value.
+	Event any `json:"event"`
+}
+
+// Option configures an HECClient during construction.
+type Option func(*HECClient)
+
+// WithGzip enables gzip compression of batch request bodies.
+func WithGzip(enabled bool) Option {
+	return func(c *HECClient) { c.Gzip = enabled }
+}
+
+// WithMaxBatch caps a single batch request's size in bytes and number of
+// events; SendBatch splits a larger []Event across multiple requests.
+func WithMaxBatch(maxBytes, maxEvents int) Option {
+	return func(c *HECClient) {
+		c.MaxBatchBytes = maxBytes
+		c.MaxBatchEvents = maxEvents
+	}
+}
+
+// WithRetry overrides the default retry policy (3 attempts, 200ms base,
+// 5s max) used for 5xx and 429 responses.
+func WithRetry(maxAttempts int, base, max time.Duration) Option {
+	return func(c *HECClient) {
+		c.MaxRetries = maxAttempts
+		c.RetryBase = base
+		c.RetryMax = max
+	}
+}
+
+// WithHTTPClient overrides the *http.Client used for requests, e.g. to
+// configure TLS or inject a tracing transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *HECClient) { c.HTTPClient = httpClient }
+}
+
+// HECClient sends events to a Splunk HTTP Event Collector endpoint.
+type HECClient struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+
+	Gzip           bool
+	MaxBatchBytes  int
+	MaxBatchEvents int
+	MaxRetries     int
+	RetryBase      time.Duration
+	RetryMax       time.Duration
+}
+
+// NewHECClient creates a client for the HEC endpoint at baseURL,
+// authenticating with the given HEC token.
+func NewHECClient(baseURL, token string, opts ...Option) (*HECClient, error) {
+	if token == "" {
+		return nil, fmt.Errorf("hec: token must not be empty")
+	}
+
+	c := &HECClient{
+		BaseURL:        strings.TrimRight(baseURL, "/"),
+		Token:          token,
+		HTTPClient:     &http.Client{Timeout: 30 * time.Second},
+		MaxBatchBytes:  defaultMaxBatchBytes,
+		MaxBatchEvents: defaultMaxBatchEvents,
+		MaxRetries:     defaultMaxRetries,
+		RetryBase:      defaultRetryBase,
+		RetryMax:       defaultRetryMax,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// SendEvent sends a single event.
+func (c *HECClient) SendEvent(ctx context.Context, event Event) error {
+	return c.SendBatch(ctx, []Event{event})
+}
+
+// SendBatch sends events to /services/collector/event, splitting them
+// into multiple batches bounded by MaxBatchBytes/MaxBatchEvents as
+// needed. Each batch is retried with exponential backoff on transport
+// errors, 5xx, and 429 responses.
+func (c *HECClient) SendBatch(ctx context.Context, events []Event) error {
+	batches, err := c.buildBatches(events)
+	if err != nil {
+		return err
+	}
+
+	for i, batch := range batches {
+		if err := c.postWithRetry(ctx, "/services/collector/event", nil, batch); err != nil {
+			return fmt.Errorf("hec: batch %d/%d failed: %w", i+1, len(batches), err)
+		}
+	}
+	return nil
+}
+
+// SendRaw posts raw, unstructured data to /services/collector/raw, tagged
+// with the given source and sourcetype. Splunk applies line-breaking and
+// timestamp extraction itself rather than treating data as pre-formed
+// events.
+func (c *HECClient) SendRaw(ctx context.Context, source, sourcetype string, data []byte) error {
+	query := map[string]string{}
+	if source != "" {
+		query["source"] = source
+	}
+	if sourcetype != "" {
+		query["sourcetype"] = sourcetype
+	}
+	return c.postWithRetry(ctx, "/services/collector/raw", query, data)
+}
+
+// buildBatches groups events into newline-delimited JSON payloads no
+// larger than MaxBatchBytes and no longer than MaxBatchEvents.
+func (c *HECClient) buildBatches(events []Event) ([][]byte, error) {
+	maxBytes := c.MaxBatchBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBatchBytes
+	}
+	maxEvents := c.MaxBatchEvents
+	if maxEvents <= 0 {
+		maxEvents = defaultMaxBatchEvents
+	}
+
+	var batches [][]byte
+	var buf bytes.Buffer
+	count := 0
+
+	flush := func() {
+		if buf.Len() > 0 {
+			batches = append(batches, append([]byte(nil), buf.Bytes()...))
+			buf.Reset()
+			count = 0
+		}
+	}
+
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("hec: failed to marshal event: %w", err)
+		}
+		if buf.Len() > 0 && (buf.Len()+len(line) > maxBytes || count >= maxEvents) {
+			flush()
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		count++
+	}
+	flush()
+
+	return batches, nil
+}
+
+// postWithRetry POSTs body to path (optionally gzip-compressed), retrying
+// on transport errors, 5xx, and 429 responses with exponential backoff
+// and full jitter.
+func (c *HECClient) postWithRetry(ctx context.Context, path string, query map[string]string, body []byte) error {
+	reqURL := c.BaseURL + path
+	if len(query) > 0 {
+		values := url.Values{}
+		for k, v := range query {
+			values.Set(k, v)
+		}
+		reqURL += "?" + values.Encode()
+	}
+
+	maxAttempts := c.MaxRetries
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		payload, encoding, err := c.encode(body)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Splunk "+c.Token)
+		req.Header.Set("Content-Type", "application/json")
+		if encoding != "" {
+			req.Header.Set("Content-Encoding", encoding)
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			lastErr = err
+		} else {
+			respBody, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+			lastErr = fmt.Errorf("hec request failed: %s - %s", resp.Status, string(respBody))
+			if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+				return lastErr
+			}
+		}
+
+		if attempt == maxAttempts-1 {
+			return lastErr
+		}
+
+		delay := backoffDelay(c.RetryBase, c.RetryMax, attempt)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	return lastErr
+}
+
+// encode gzip-compresses body when Gzip is enabled, returning the
+// Content-Encoding value to set (empty when uncompressed).
+func (c *HECClient) encode(body []byte) ([]byte, string, error) {
+	if !c.Gzip {
+		return body, "", nil
+	}
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(body); err != nil {
+		return nil, "", fmt.Errorf("hec: failed to gzip batch: %w", err)
+	}
+	if err := gw.Close(); err != nil {
+		return nil, "", fmt.Errorf("hec: failed to gzip batch: %w", err)
+	}
+	return buf.Bytes(), "gzip", nil
+}
+
+// backoffDelay computes an exponential backoff delay with full jitter: a
+// random duration in [0, min(max, base*2^attempt)).
+func backoffDelay(base, max time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	delay := base
+	for i := 0; i < attempt; i++ {
+		delay *= 2
+		if delay > max || delay <= 0 {
+			delay = max
+			break
+		}
+	}
+	if delay > max {
+		delay = max
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay)))
+}
@@ -0,0 +1,177 @@
+package hec
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testHECClient(t *testing.T, server *httptest.Server, opts ...Option) *HECClient {
+	t.Helper()
+	opts = append([]Option{WithHTTPClient(server.Client())}, opts...)
+	c, err := NewHECClient(server.URL, "test-token", opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestSendBatch_SplitsAcrossRequests(t *testing.T) {
+	var gotBatches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBatches++
+		if r.URL.Path != "/services/collector/event" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Splunk test-token" {
+			t.Errorf("unexpected Authorization header: %s", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := testHECClient(t, server, WithMaxBatch(0, 1))
+	events := []Event{
+		{Event: "a"},
+		{Event: "b"},
+	}
+	if err := c.SendBatch(context.Background(), events); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotBatches != 2 {
+		t.Errorf("expected 2 requests, got %d", gotBatches)
+	}
+}
+
+func TestSendBatch_GzipsWhenEnabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("expected Content-Encoding: gzip, got %q", r.Header.Get("Content-Encoding"))
+		}
+		gr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read gzip body: %v", err)
+		}
+		body, err := io.ReadAll(gr)
+		if err != nil {
+			t.Fatalf("failed to decompress body: %v", err)
+		}
+		if !strings.Contains(string(body), `"event":"hello"`) {
+			t.Errorf("unexpected decompressed body: %s", body)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := testHECClient(t, server, WithGzip(true))
+	if err := c.SendBatch(context.Background(), []Event{{Event: "hello"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSendBatch_RetriesOnServerError(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := testHECClient(t, server, WithRetry(3, time.Millisecond, 5*time.Millisecond))
+	if err := c.SendBatch(context.Background(), []Event{{Event: "a"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+// TestSendRaw_EncodesQueryParameters is a regression test for the
+// SendRaw query-encoding fix: source/sourcetype values containing
+// reserved characters must be percent-encoded, not concatenated raw
+// into the query string.
+func TestSendRaw_EncodesQueryParameters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/collector/raw" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("source"); got != "app=1 & 2" {
+			t.Errorf("unexpected source: %q", got)
+		}
+		if got := r.URL.Query().Get("sourcetype"); got != "my/type+x" {
+			t.Errorf("unexpected sourcetype: %q", got)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "raw payload" {
+			t.Errorf("unexpected body: %s", body)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := testHECClient(t, server)
+	err := c.SendRaw(context.Background(), "app=1 & 2", "my/type+x", []byte("raw payload"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSendRaw_OmitsEmptyQueryParameters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "" {
+			t.Errorf("expected no query string, got %q", r.URL.RawQuery)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := testHECClient(t, server)
+	if err := c.SendRaw(context.Background(), "", "", []byte("data")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNewHECClient_RequiresToken(t *testing.T) {
+	if _, err := NewHECClient("http://localhost", ""); err == nil {
+		t.Fatalf("expected an error for an empty token")
+	}
+}
+
+func TestWriter_FlushesOnClose(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := testHECClient(t, server, WithRetry(1, time.Millisecond, time.Millisecond))
+	writer := c.Writer(WriterOptions{Source: "mylog", FlushInterval: time.Hour})
+
+	if _, err := writer.Write([]byte("line one")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+		if err := json.Unmarshal([]byte(line), &decoded); err != nil {
+			t.Fatalf("failed to decode flushed event: %v", err)
+		}
+	}
+	if decoded["source"] != "mylog" || decoded["event"] != "line one" {
+		t.Errorf("unexpected flushed event: %v", decoded)
+	}
+}
@@ -0,0 +1,148 @@
+package hec
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultWriterFlushInterval = 2 * time.Second
+
+// WriterOptions configures a Writer returned by HECClient.Writer.
+type WriterOptions struct {
+	// Index, Source, Sourcetype, and Host are applied to every event
+	// written through the Writer.
+	Index      string
+	Source     string
+	Sourcetype string
+	Host       string
+	// FlushInterval bounds how long a write can sit buffered before being
+	// sent, independent of MaxBatchEvents/MaxBatchBytes. Defaults to 2
+	// seconds when zero.
+	FlushInterval time.Duration
+	// ErrorHandler, if set, is called with the error from any batch the
+	// background flush loop fails to send. Writer.Write does not block on
+	// delivery, so this is the only way to observe send failures.
+	ErrorHandler func(error)
+}
+
+// Writer adapts an HECClient to the io.Writer interface for streaming
+// ingestion: each Write call enqueues its bytes as an event and returns
+// immediately, while a background goroutine flushes queued events as a
+// batch once MaxBatchEvents/MaxBatchBytes or FlushInterval is reached.
+type Writer struct {
+	client *HECClient
+	opts   WriterOptions
+
+	mu     sync.Mutex
+	queue  []Event
+	queued int // approximate buffered bytes, for MaxBatchBytes accounting
+
+	flushNow chan struct{}
+	done     chan struct{}
+	stopped  chan struct{}
+}
+
+// Writer returns an io.Writer that batches writes and sends them
+// asynchronously via c.SendBatch. Call Close to flush any buffered
+// events and stop the background flush loop.
+func (c *HECClient) Writer(opts WriterOptions) *Writer {
+	w := &Writer{
+		client:   c,
+		opts:     opts,
+		flushNow: make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write enqueues p as the payload of a new event and returns immediately;
+// delivery happens asynchronously on the Writer's background goroutine.
+// It always reports success, since HEC delivery failures surface through
+// WriterOptions.ErrorHandler rather than the io.Writer contract.
+func (w *Writer) Write(p []byte) (int, error) {
+	event := Event{
+		Index:      w.opts.Index,
+		Source:     w.opts.Source,
+		Sourcetype: w.opts.Sourcetype,
+		Host:       w.opts.Host,
+		Event:      string(p),
+	}
+
+	maxBytes := w.client.MaxBatchBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBatchBytes
+	}
+	maxEvents := w.client.MaxBatchEvents
+	if maxEvents <= 0 {
+		maxEvents = defaultMaxBatchEvents
+	}
+
+	w.mu.Lock()
+	w.queue = append(w.queue, event)
+	w.queued += len(p)
+	full := len(w.queue) >= maxEvents || w.queued >= maxBytes
+	w.mu.Unlock()
+
+	if full {
+		select {
+		case w.flushNow <- struct{}{}:
+		default:
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close flushes any buffered events and stops the background flush loop.
+func (w *Writer) Close() error {
+	close(w.done)
+	<-w.stopped
+	return nil
+}
+
+// run is the Writer's background flush loop: it sends whatever is queued
+// every FlushInterval, or sooner if Write signals the batch is full.
+func (w *Writer) run() {
+	defer close(w.stopped)
+
+	interval := w.opts.FlushInterval
+	if interval <= 0 {
+		interval = defaultWriterFlushInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			w.flush()
+			return
+		case <-ticker.C:
+			w.flush()
+		case <-w.flushNow:
+			w.flush()
+		}
+	}
+}
+
+// flush sends everything currently queued as a single SendBatch call.
+func (w *Writer) flush() {
+	w.mu.Lock()
+	batch := w.queue
+	w.queue = nil
+	w.queued = 0
+	w.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	if err := w.client.SendBatch(context.Background(), batch); err != nil {
+		if w.opts.ErrorHandler != nil {
+			w.opts.ErrorHandler(err)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package splunk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildEventBatches_SplitsOnMaxEvents(t *testing.T) {
+	c := &Client{MaxBatchEvents: 2}
+	events := []Event{
+		{Time: 1, Event: map[string]any{"msg": "a"}},
+		{Time: 2, Event: map[string]any{"msg": "b"}},
+		{Time: 3, Event: map[string]any{"msg": "c"}},
+	}
+
+	batches, err := c.buildEventBatches("main", events)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batches) != 2 {
+		t.Fatalf("expected 2 batches, got %d", len(batches))
+	}
+	if n := bytes.Count(batches[0], []byte("\n")); n != 2 {
+		t.Errorf("expected first batch to hold 2 events, got %d lines", n)
+	}
+	if n := bytes.Count(batches[1], []byte("\n")); n != 1 {
+		t.Errorf("expected second batch to hold 1 event, got %d lines", n)
+	}
+}
+
+func TestBuildEventBatches_SplitsOnMaxBytes(t *testing.T) {
+	// Each encoded event is well over 10 bytes, so MaxBatchBytes of 10
+	// forces every event into its own batch.
+	c := &Client{MaxBatchBytes: 10}
+	events := []Event{
+		{Time: 1, Event: map[string]any{"msg": "aaaaaaaaaa"}},
+		{Time: 2, Event: map[string]any{"msg": "bbbbbbbbbb"}},
+	}
+
+	batches, err := c.buildEventBatches("main", events)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batches) != 2 {
+		t.Fatalf("expected 2 batches, got %d", len(batches))
+	}
+}
+
+func TestBuildEventBatches_OmitsZeroTime(t *testing.T) {
+	c := &Client{}
+	events := []Event{
+		{Event: map[string]any{"msg": "a"}},
+	}
+
+	batches, err := c.buildEventBatches("main", events)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batches) != 1 {
+		t.Fatalf("expected a single batch, got %d", len(batches))
+	}
+	if bytes.Contains(batches[0], []byte(`"time"`)) {
+		t.Errorf("expected a zero Time to be omitted, got %s", batches[0])
+	}
+}
+
+func TestBuildEventBatches_SingleBatchUnderLimits(t *testing.T) {
+	c := &Client{}
+	events := []Event{
+		{Time: 1, Event: map[string]any{"msg": "a"}},
+		{Time: 2, Event: map[string]any{"msg": "b"}},
+	}
+
+	batches, err := c.buildEventBatches("main", events)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batches) != 1 {
+		t.Fatalf("expected a single batch, got %d", len(batches))
+	}
+	if n := bytes.Count(batches[0], []byte("\n")); n != 2 {
+		t.Errorf("expected 2 events in the batch, got %d lines", n)
+	}
+}
+
+func TestSendEvents_SplitsAcrossBatches(t *testing.T) {
+	var gotBatches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBatches++
+		if r.URL.Path != "/services/collector/event" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]any{"text": "Success", "code": 0})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		BaseURL:        server.URL,
+		Token:          "test-token",
+		MaxBatchEvents: 1,
+		HTTPClient:     server.Client(),
+	}
+
+	events := []Event{
+		{Time: 1, Event: map[string]any{"a": 1}},
+		{Time: 2, Event: map[string]any{"b": 2}},
+	}
+	ackIDs, err := c.SendEvents("main", events)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ackIDs) != 0 {
+		t.Errorf("expected no ack IDs when UseAck is false, got %v", ackIDs)
+	}
+	if gotBatches != 2 {
+		t.Errorf("expected 2 requests, got %d", gotBatches)
+	}
+}
+
+func TestSendEvents_PartialFailureReturnsBatchError(t *testing.T) {
+	var call int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call++
+		if call == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			io.WriteString(w, "boom")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]any{"text": "Success", "code": 0})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		BaseURL:        server.URL,
+		Token:          "test-token",
+		MaxBatchEvents: 1,
+		HTTPClient:     server.Client(),
+	}
+
+	events := []Event{
+		{Time: 1, Event: map[string]any{"a": 1}},
+		{Time: 2, Event: map[string]any{"b": 2}},
+	}
+	_, err := c.SendEvents("main", events)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected a *BatchError, got %v (%T)", err, err)
+	}
+	if len(batchErr.Failed) != 1 || len(batchErr.Succeeded) != 1 {
+		t.Errorf("expected 1 failed and 1 succeeded batch, got %+v", batchErr)
+	}
+}
+
+func TestWaitForAcks_PollsUntilAcked(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/services/collector/ack" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if calls == 1 {
+			json.NewEncoder(w).Encode(map[string]any{"acks": map[string]bool{"1": false}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"acks": map[string]bool{"1": true}})
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Token: "test-token", UseAck: true, HTTPClient: server.Client()}
+	err := c.WaitForAcks(context.Background(), []int64{1}, time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 poll requests, got %d", calls)
+	}
+}
+
+func TestWaitForAcks_NonOKStatusReturnsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		io.WriteString(w, "boom")
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Token: "test-token", UseAck: true, HTTPClient: server.Client()}
+	err := c.WaitForAcks(context.Background(), []int64{1}, time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "failed to poll acks") {
+		t.Fatalf("expected a poll-acks error, got %v", err)
+	}
+}
@@ -0,0 +1,366 @@
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// JobOptions configures a search job created with CreateJob.
+type JobOptions struct {
+	// ExecMode is the Splunk exec_mode for the job: "normal" (async,
+	// default) or "blocking".
+	ExecMode string
+	// EarliestTime and LatestTime set the job's search time window using
+	// Splunk time modifiers (e.g. "-15m", "now").
+	EarliestTime string
+	LatestTime   string
+}
+
+// SearchJob represents an in-progress or completed search job created via
+// POST /services/search/jobs.
+type SearchJob struct {
+	SID    string
+	client *Client
+}
+
+// JobStatus is the parsed status of a search job, as reported by
+// GET /services/search/jobs/{sid}.
+type JobStatus struct {
+	IsDone        bool
+	DispatchState string
+	EventCount    int
+	ResultCount   int
+	DoneProgress  float64
+}
+
+// SearchRow is a single result row from a search job, keyed by field name.
+type SearchRow map[string]any
+
+// SearchResults holds a page of results from Job.Results, along with the
+// field names Splunk reported for the result set.
+type SearchResults struct {
+	Fields []string
+	Rows   []SearchRow
+}
+
+// createJobResponse is the XML body returned by POST /services/search/jobs.
+type createJobResponse struct {
+	SID string `xml:"sid"`
+}
+
+// CreateJob dispatches a new search job and returns a handle to it. The
+// job runs asynchronously on the Splunk side; use Job.Wait or Job.Status
+// to track its progress.
+func (c *Client) CreateJob(ctx context.Context, query string, opts JobOptions) (*SearchJob, error) {
+	execMode := opts.ExecMode
+	if execMode == "" {
+		execMode = "normal"
+	}
+
+	params := []string{
+		fmt.Sprintf("search=%s", url.QueryEscape(query)),
+		fmt.Sprintf("exec_mode=%s", url.QueryEscape(execMode)),
+	}
+	if opts.EarliestTime != "" {
+		params = append(params, fmt.Sprintf("earliest_time=%s", url.QueryEscape(opts.EarliestTime)))
+	}
+	if opts.LatestTime != "" {
+		params = append(params, fmt.Sprintf("latest_time=%s", url.QueryEscape(opts.LatestTime)))
+	}
+
+	reqBody := strings.Join(params, "&")
+	headers, err := c.authHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	headers["Content-Type"] = "application/x-www-form-urlencoded"
+
+	jobsURL := c.BaseURL + "/services/search/jobs"
+	resp, err := c.doRequest(ctx, "POST", jobsURL, headers, func() io.Reader { return strings.NewReader(reqBody) })
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("create job failed: %s", resp.Status)
+	}
+
+	var parsed createJobResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse job SID: %w", err)
+	}
+	if parsed.SID == "" {
+		return nil, fmt.Errorf("create job response did not contain a sid")
+	}
+
+	return &SearchJob{SID: parsed.SID, client: c}, nil
+}
+
+// jobStatusResponse models the subset of the Atom feed returned by
+// GET /services/search/jobs/{sid} that JobStatus cares about.
+type jobStatusResponse struct {
+	Content struct {
+		Dict struct {
+			Keys []struct {
+				Name string `xml:"name,attr"`
+				Text string `xml:",chardata"`
+			} `xml:"key"`
+		} `xml:"dict"`
+	} `xml:"content"`
+}
+
+// Status fetches the job's current dispatch state from
+// GET /services/search/jobs/{sid}.
+func (j *SearchJob) Status(ctx context.Context) (*JobStatus, error) {
+	headers, err := j.client.authHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statusURL := fmt.Sprintf("%s/services/search/jobs/%s", j.client.BaseURL, j.SID)
+	resp, err := j.client.doRequest(ctx, "GET", statusURL, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("job status request failed: %s", resp.Status)
+	}
+
+	var parsed jobStatusResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse job status: %w", err)
+	}
+
+	status := &JobStatus{}
+	for _, key := range parsed.Content.Dict.Keys {
+		switch key.Name {
+		case "isDone":
+			status.IsDone = key.Text == "1"
+		case "dispatchState":
+			status.DispatchState = key.Text
+		case "eventCount":
+			fmt.Sscanf(key.Text, "%d", &status.EventCount)
+		case "resultCount":
+			fmt.Sscanf(key.Text, "%d", &status.ResultCount)
+		case "doneProgress":
+			fmt.Sscanf(key.Text, "%f", &status.DoneProgress)
+		}
+	}
+	return status, nil
+}
+
+// Wait polls Status at pollInterval until the job reports done or ctx is
+// canceled.
+func (j *SearchJob) Wait(ctx context.Context, pollInterval time.Duration) error {
+	for {
+		status, err := j.Status(ctx)
+		if err != nil {
+			return err
+		}
+		if status.IsDone {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// resultsResponse models the output_mode=json body returned by
+// GET /services/search/jobs/{sid}/results.
+type resultsResponse struct {
+	Fields []struct {
+		Name string `json:"name"`
+	} `json:"fields"`
+	Results []SearchRow `json:"results"`
+}
+
+// Results fetches a single page of results starting at offset, returning
+// at most count rows. Use the Events iterator to transparently page
+// through an entire result set.
+func (j *SearchJob) Results(ctx context.Context, offset, count int) (*SearchResults, error) {
+	headers, err := j.client.authHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resultsURL := fmt.Sprintf("%s/services/search/jobs/%s/results?output_mode=json&offset=%d&count=%d",
+		j.client.BaseURL, j.SID, offset, count)
+	resp, err := j.client.doRequest(ctx, "GET", resultsURL, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("job results request failed: %s", resp.Status)
+	}
+
+	var parsed resultsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse job results: %w", err)
+	}
+
+	fields := make([]string, len(parsed.Fields))
+	for i, f := range parsed.Fields {
+		fields[i] = f.Name
+	}
+	return &SearchResults{Fields: fields, Rows: parsed.Results}, nil
+}
+
+// ResultReader fetches a single page of results starting at offset, at
+// most count rows, in the given output_mode ("" defaults to "json"), and
+// streams it through a ResultReader rather than buffering the page into
+// a SearchResults. Use this instead of Results for large pages, typed
+// decoding via ResultReader.Decode, or output modes other than JSON.
+func (j *SearchJob) ResultReader(ctx context.Context, offset, count int, outputMode string) (*ResultReader, error) {
+	headers, err := j.client.authHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := outputMode
+	if mode == "" {
+		mode = "json"
+	}
+	resultsURL := fmt.Sprintf("%s/services/search/jobs/%s/results?output_mode=%s&offset=%d&count=%d",
+		j.client.BaseURL, j.SID, url.QueryEscape(mode), offset, count)
+	resp, err := j.client.doRequest(ctx, "GET", resultsURL, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("job results request failed: %s", resp.Status)
+	}
+
+	return NewResultReader(resp.Body, mode), nil
+}
+
+// Events returns an iterator function that transparently pages through the
+// job's entire result set, pageSize rows at a time. Each call returns the
+// next row; the returned bool is false once the result set is exhausted or
+// an error occurs, in which case err reports the cause (nil on normal
+// exhaustion).
+func (j *SearchJob) Events(ctx context.Context, pageSize int) func() (SearchRow, bool, error) {
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+
+	offset := 0
+	var page []SearchRow
+	pageIdx := 0
+	done := false
+
+	return func() (SearchRow, bool, error) {
+		for {
+			if pageIdx < len(page) {
+				row := page[pageIdx]
+				pageIdx++
+				return row, true, nil
+			}
+			if done {
+				return nil, false, nil
+			}
+
+			results, err := j.Results(ctx, offset, pageSize)
+			if err != nil {
+				return nil, false, err
+			}
+			page = results.Rows
+			pageIdx = 0
+			offset += len(results.Rows)
+			if len(results.Rows) < pageSize {
+				done = true
+			}
+			if len(page) == 0 {
+				return nil, false, nil
+			}
+		}
+	}
+}
+
+// control posts a search job control action, e.g. "cancel", "pause",
+// "finalize", to /services/search/jobs/{sid}/control.
+func (j *SearchJob) control(ctx context.Context, action string) error {
+	headers, err := j.client.authHeaders(ctx)
+	if err != nil {
+		return err
+	}
+	headers["Content-Type"] = "application/x-www-form-urlencoded"
+
+	controlURL := fmt.Sprintf("%s/services/search/jobs/%s/control", j.client.BaseURL, j.SID)
+	resp, err := j.client.doRequest(ctx, "POST", controlURL, headers, func() io.Reader { return strings.NewReader("action=" + action) })
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("job %s failed: %s", action, resp.Status)
+	}
+	return nil
+}
+
+// Cancel stops the job and removes its results from Splunk.
+func (j *SearchJob) Cancel(ctx context.Context) error {
+	return j.control(ctx, "cancel")
+}
+
+// Pause suspends execution of the job. A paused job can be resumed with
+// Unpause.
+func (j *SearchJob) Pause(ctx context.Context) error {
+	return j.control(ctx, "pause")
+}
+
+// Unpause resumes a job previously suspended with Pause.
+func (j *SearchJob) Unpause(ctx context.Context) error {
+	return j.control(ctx, "unpause")
+}
+
+// Finalize stops the job early and makes the results gathered so far
+// available, as if the search had completed normally.
+func (j *SearchJob) Finalize(ctx context.Context) error {
+	return j.control(ctx, "finalize")
+}
+
+// Preview fetches a single snapshot of the job's current results from
+// results_preview, without waiting for the job to finish. Use
+// StreamPreview instead to receive incremental updates as they arrive.
+func (j *SearchJob) Preview(ctx context.Context) (*SearchResults, error) {
+	return j.fetchPreviewPage(ctx, 0, 0)
+}
+
+// Delete removes the job's artifacts without stopping it if still running.
+func (j *SearchJob) Delete(ctx context.Context) error {
+	headers, err := j.client.authHeaders(ctx)
+	if err != nil {
+		return err
+	}
+
+	deleteURL := fmt.Sprintf("%s/services/search/jobs/%s", j.client.BaseURL, j.SID)
+	resp, err := j.client.doRequest(ctx, "DELETE", deleteURL, headers, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("delete job failed: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,402 @@
+// Package kvstore provides a Go-idiomatic client for Splunk's App Key
+// Value Store, exposing collections under
+// /servicesNS/{owner}/{app}/storage/collections as a document store
+// rather than raw REST calls. It authenticates through an existing
+// splunk.Client, reusing its session and retry behavior rather than
+// managing its own credentials the way the hec subpackage does.
+package kvstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"splunk-go-sdk/pkg/splunk"
+)
+
+// Collections returns a handle for managing KV Store collections within
+// app, scoped to owner ("nobody" for collections shared across users,
+// which is the common case for KV Store).
+func Collections(client *splunk.Client, owner, app string) *CollectionsService {
+	return &CollectionsService{client: client, owner: owner, app: app}
+}
+
+// CollectionsService manages KV Store collections within a single
+// owner/app namespace.
+type CollectionsService struct {
+	client *splunk.Client
+	owner  string
+	app    string
+}
+
+// Create declares a new collection named name. schema maps field names
+// to KV Store field types ("number", "string", "bool", "time", or
+// "cidr") for statically typed fields; pass nil for a schemaless
+// collection where every field is inferred from its documents.
+func (s *CollectionsService) Create(ctx context.Context, name string, schema map[string]string) error {
+	params := url.Values{"name": {name}}
+	for field, typ := range schema {
+		params.Set("field."+field, typ)
+	}
+
+	configURL := fmt.Sprintf("%s/servicesNS/%s/%s/storage/collections/config?output_mode=json",
+		s.client.BaseURL, url.PathEscape(s.owner), url.PathEscape(s.app))
+	return s.postForm(ctx, configURL, params, "create collection")
+}
+
+// Delete removes a collection and all of its documents.
+func (s *CollectionsService) Delete(ctx context.Context, name string) error {
+	headers, err := s.client.AuthHeaders(ctx)
+	if err != nil {
+		return err
+	}
+
+	configURL := fmt.Sprintf("%s/servicesNS/%s/%s/storage/collections/config/%s?output_mode=json",
+		s.client.BaseURL, url.PathEscape(s.owner), url.PathEscape(s.app), url.PathEscape(name))
+	resp, err := s.client.Do(ctx, "DELETE", configURL, headers, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kvstore: delete collection failed: %s", resp.Status)
+	}
+	return nil
+}
+
+// Collection returns a handle for collection name's data within s's
+// owner/app namespace. Create must have been called first.
+func (s *CollectionsService) Collection(name string) *Collection {
+	return &Collection{client: s.client, owner: s.owner, app: s.app, name: name}
+}
+
+func (s *CollectionsService) postForm(ctx context.Context, reqURL string, params url.Values, what string) error {
+	headers, err := s.client.AuthHeaders(ctx)
+	if err != nil {
+		return err
+	}
+	headers["Content-Type"] = "application/x-www-form-urlencoded"
+
+	body := params.Encode()
+	resp, err := s.client.Do(ctx, "POST", reqURL, headers, func() io.Reader { return strings.NewReader(body) })
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("kvstore: %s failed: %s - %s", what, resp.Status, string(respBody))
+	}
+	return nil
+}
+
+// QueryOpts refines a Find call.
+type QueryOpts struct {
+	// Sort is a comma-separated list of "field:1" (ascending) or
+	// "field:-1" (descending) terms, e.g. "name:1,age:-1".
+	Sort string
+	// Limit caps the number of documents returned. Zero means no limit.
+	Limit int
+	// Skip omits this many matching documents before the first one
+	// returned, for pagination alongside Limit.
+	Skip int
+	// Fields projects the response to only these field names. Empty
+	// means every field.
+	Fields []string
+}
+
+// Collection is a handle to a single KV Store collection's documents.
+type Collection struct {
+	client *splunk.Client
+	owner  string
+	app    string
+	name   string
+}
+
+func (c *Collection) dataURL(suffix string, params url.Values) string {
+	u := fmt.Sprintf("%s/servicesNS/%s/%s/storage/collections/data/%s%s",
+		c.client.BaseURL, url.PathEscape(c.owner), url.PathEscape(c.app), url.PathEscape(c.name), suffix)
+	if params != nil {
+		u += "?" + params.Encode()
+	}
+	return u
+}
+
+// Insert adds doc as a new document, returning the _key Splunk assigned
+// it.
+func (c *Collection) Insert(ctx context.Context, doc map[string]any) (string, error) {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("kvstore: failed to marshal document: %w", err)
+	}
+
+	headers, err := c.client.AuthHeaders(ctx)
+	if err != nil {
+		return "", err
+	}
+	headers["Content-Type"] = "application/json"
+
+	reqURL := c.dataURL("", url.Values{"output_mode": {"json"}})
+	resp, err := c.client.Do(ctx, "POST", reqURL, headers, func() io.Reader { return bytes.NewReader(body) })
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("kvstore: insert failed: %s - %s", resp.Status, string(respBody))
+	}
+
+	var parsed struct {
+		Key string `json:"_key"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("kvstore: failed to parse insert response: %w", err)
+	}
+	return parsed.Key, nil
+}
+
+// InsertBatch adds multiple documents in a single transactional request
+// via the batch_save endpoint, returning each document's assigned _key
+// in the same order as docs.
+func (c *Collection) InsertBatch(ctx context.Context, docs []map[string]any) ([]string, error) {
+	body, err := json.Marshal(docs)
+	if err != nil {
+		return nil, fmt.Errorf("kvstore: failed to marshal documents: %w", err)
+	}
+
+	headers, err := c.client.AuthHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	headers["Content-Type"] = "application/json"
+
+	reqURL := c.dataURL("/batch_save", url.Values{"output_mode": {"json"}})
+	resp, err := c.client.Do(ctx, "POST", reqURL, headers, func() io.Reader { return bytes.NewReader(body) })
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("kvstore: batch insert failed: %s - %s", resp.Status, string(respBody))
+	}
+
+	var keys []string
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return nil, fmt.Errorf("kvstore: failed to parse batch insert response: %w", err)
+	}
+	return keys, nil
+}
+
+// Find returns documents matching query, a Mongo-style query object
+// (e.g. map[string]any{"age": map[string]any{"$gt": 21}}); a nil query
+// matches every document. opts refines sorting, pagination, and field
+// projection.
+func (c *Collection) Find(ctx context.Context, query map[string]any, opts QueryOpts) ([]map[string]any, error) {
+	params := url.Values{"output_mode": {"json"}}
+	if query != nil {
+		q, err := json.Marshal(query)
+		if err != nil {
+			return nil, fmt.Errorf("kvstore: failed to marshal query: %w", err)
+		}
+		params.Set("query", string(q))
+	}
+	if opts.Sort != "" {
+		params.Set("sort", opts.Sort)
+	}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Skip > 0 {
+		params.Set("skip", strconv.Itoa(opts.Skip))
+	}
+	if len(opts.Fields) > 0 {
+		params.Set("fields", strings.Join(opts.Fields, ","))
+	}
+
+	headers, err := c.client.AuthHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(ctx, "GET", c.dataURL("", params), headers, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("kvstore: find failed: %s - %s", resp.Status, string(respBody))
+	}
+
+	var docs []map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&docs); err != nil {
+		return nil, fmt.Errorf("kvstore: failed to parse find response: %w", err)
+	}
+	return docs, nil
+}
+
+// FindByID fetches a single document by its _key, returning (nil, nil)
+// if no document has that key.
+func (c *Collection) FindByID(ctx context.Context, id string) (map[string]any, error) {
+	headers, err := c.client.AuthHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := c.dataURL("/"+url.PathEscape(id), url.Values{"output_mode": {"json"}})
+	resp, err := c.client.Do(ctx, "GET", reqURL, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("kvstore: find by id failed: %s - %s", resp.Status, string(respBody))
+	}
+
+	var doc map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("kvstore: failed to parse document: %w", err)
+	}
+	return doc, nil
+}
+
+// Update replaces the document with _key id.
+func (c *Collection) Update(ctx context.Context, id string, doc map[string]any) error {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("kvstore: failed to marshal document: %w", err)
+	}
+
+	headers, err := c.client.AuthHeaders(ctx)
+	if err != nil {
+		return err
+	}
+	headers["Content-Type"] = "application/json"
+
+	reqURL := c.dataURL("/"+url.PathEscape(id), url.Values{"output_mode": {"json"}})
+	resp, err := c.client.Do(ctx, "POST", reqURL, headers, func() io.Reader { return bytes.NewReader(body) })
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("kvstore: update failed: %s - %s", resp.Status, string(respBody))
+	}
+	return nil
+}
+
+// Delete removes every document matching query. A nil query deletes
+// every document in the collection.
+func (c *Collection) Delete(ctx context.Context, query map[string]any) error {
+	params := url.Values{"output_mode": {"json"}}
+	if query != nil {
+		q, err := json.Marshal(query)
+		if err != nil {
+			return fmt.Errorf("kvstore: failed to marshal query: %w", err)
+		}
+		params.Set("query", string(q))
+	}
+
+	headers, err := c.client.AuthHeaders(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(ctx, "DELETE", c.dataURL("", params), headers, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("kvstore: delete failed: %s - %s", resp.Status, string(respBody))
+	}
+	return nil
+}
+
+// DeleteByID removes a single document by its _key.
+func (c *Collection) DeleteByID(ctx context.Context, id string) error {
+	headers, err := c.client.AuthHeaders(ctx)
+	if err != nil {
+		return err
+	}
+
+	reqURL := c.dataURL("/"+url.PathEscape(id), url.Values{"output_mode": {"json"}})
+	resp, err := c.client.Do(ctx, "DELETE", reqURL, headers, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("kvstore: delete by id failed: %s - %s", resp.Status, string(respBody))
+	}
+	return nil
+}
+
+// CreateIndex declares an index named name over fields, where each value
+// is 1 (ascending) or -1 (descending), e.g. {"name": 1, "age": -1}. KV
+// Store indexes accelerate Find queries that filter or sort on the
+// indexed fields.
+func (c *Collection) CreateIndex(ctx context.Context, name string, fields map[string]int) error {
+	spec, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("kvstore: failed to marshal index fields: %w", err)
+	}
+	return c.setIndex(ctx, name, string(spec))
+}
+
+// DeleteIndex removes a previously created index.
+func (c *Collection) DeleteIndex(ctx context.Context, name string) error {
+	return c.setIndex(ctx, name, "")
+}
+
+// setIndex sets (or, given an empty spec, clears) the collection
+// config's accelerated_fields.<name> stanza.
+func (c *Collection) setIndex(ctx context.Context, name, spec string) error {
+	params := url.Values{}
+	params.Set("accelerated_fields."+name, spec)
+
+	headers, err := c.client.AuthHeaders(ctx)
+	if err != nil {
+		return err
+	}
+	headers["Content-Type"] = "application/x-www-form-urlencoded"
+
+	configURL := fmt.Sprintf("%s/servicesNS/%s/%s/storage/collections/config/%s?output_mode=json",
+		c.client.BaseURL, url.PathEscape(c.owner), url.PathEscape(c.app), url.PathEscape(c.name))
+	body := params.Encode()
+	resp, err := c.client.Do(ctx, "POST", configURL, headers, func() io.Reader { return strings.NewReader(body) })
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("kvstore: index update failed: %s - %s", resp.Status, string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package kvstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"splunk-go-sdk/pkg/splunk"
+)
+
+func testClientAgainst(server *httptest.Server) *splunk.Client {
+	return &splunk.Client{BaseURL: server.URL, Token: "test-token", HTTPClient: server.Client()}
+}
+
+func TestCollectionsCreate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/nobody/search/storage/collections/config" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "name=orders") || !strings.Contains(string(body), "field.age=number") {
+			t.Errorf("unexpected body: %s", body)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	coll := Collections(testClientAgainst(server), "nobody", "search")
+	err := coll.Create(context.Background(), "orders", map[string]string{"age": "number"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCollectionsDelete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("expected DELETE, got %s", r.Method)
+		}
+		if r.URL.Path != "/servicesNS/nobody/search/storage/collections/config/orders" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	coll := Collections(testClientAgainst(server), "nobody", "search")
+	if err := coll.Delete(context.Background(), "orders"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCollectionInsert(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/nobody/search/storage/collections/data/orders" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		io.WriteString(w, `{"_key":"abc123"}`)
+	}))
+	defer server.Close()
+
+	coll := Collections(testClientAgainst(server), "nobody", "search").Collection("orders")
+	key, err := coll.Insert(context.Background(), map[string]any{"item": "widget"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key != "abc123" {
+		t.Errorf("unexpected key: %q", key)
+	}
+}
+
+func TestCollectionInsertBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/nobody/search/storage/collections/data/orders/batch_save" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		io.WriteString(w, `["k1","k2"]`)
+	}))
+	defer server.Close()
+
+	coll := Collections(testClientAgainst(server), "nobody", "search").Collection("orders")
+	keys, err := coll.InsertBatch(context.Background(), []map[string]any{{"item": "a"}, {"item": "b"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "k1" || keys[1] != "k2" {
+		t.Errorf("unexpected keys: %v", keys)
+	}
+}
+
+func TestCollectionFind(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("query") != `{"age":{"$gt":21}}` {
+			t.Errorf("unexpected query: %s", r.URL.Query().Get("query"))
+		}
+		if r.URL.Query().Get("sort") != "age:-1" {
+			t.Errorf("unexpected sort: %s", r.URL.Query().Get("sort"))
+		}
+		io.WriteString(w, `[{"_key":"a","age":30}]`)
+	}))
+	defer server.Close()
+
+	coll := Collections(testClientAgainst(server), "nobody", "search").Collection("orders")
+	docs, err := coll.Find(context.Background(), map[string]any{"age": map[string]any{"$gt": 21}}, QueryOpts{Sort: "age:-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(docs) != 1 || docs[0]["_key"] != "a" {
+		t.Errorf("unexpected docs: %v", docs)
+	}
+}
+
+func TestCollectionFindByID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/nobody/search/storage/collections/data/orders/abc123" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		io.WriteString(w, `{"_key":"abc123","item":"widget"}`)
+	}))
+	defer server.Close()
+
+	coll := Collections(testClientAgainst(server), "nobody", "search").Collection("orders")
+	doc, err := coll.FindByID(context.Background(), "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc["item"] != "widget" {
+		t.Errorf("unexpected doc: %v", doc)
+	}
+}
+
+func TestCollectionFindByID_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	coll := Collections(testClientAgainst(server), "nobody", "search").Collection("orders")
+	doc, err := coll.FindByID(context.Background(), "missing")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc != nil {
+		t.Errorf("expected nil doc for a missing key, got %v", doc)
+	}
+}
+
+func TestCollectionUpdate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/nobody/search/storage/collections/data/orders/abc123" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	coll := Collections(testClientAgainst(server), "nobody", "search").Collection("orders")
+	err := coll.Update(context.Background(), "abc123", map[string]any{"item": "gadget"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCollectionDelete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("expected DELETE, got %s", r.Method)
+		}
+		if r.URL.Query().Get("query") != `{"item":"widget"}` {
+			t.Errorf("unexpected query: %s", r.URL.Query().Get("query"))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	coll := Collections(testClientAgainst(server), "nobody", "search").Collection("orders")
+	err := coll.Delete(context.Background(), map[string]any{"item": "widget"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCollectionDeleteByID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("expected DELETE, got %s", r.Method)
+		}
+		if r.URL.Path != "/servicesNS/nobody/search/storage/collections/data/orders/abc123" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	coll := Collections(testClientAgainst(server), "nobody", "search").Collection("orders")
+	err := coll.DeleteByID(context.Background(), "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCollectionCreateIndex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "accelerated_fields.by_age=") {
+			t.Errorf("unexpected body: %s", body)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	coll := Collections(testClientAgainst(server), "nobody", "search").Collection("orders")
+	err := coll.CreateIndex(context.Background(), "by_age", map[string]int{"age": -1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCollectionDeleteIndex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "accelerated_fields.by_age=" {
+			t.Errorf("expected the index spec to be cleared, got %s", body)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	coll := Collections(testClientAgainst(server), "nobody", "search").Collection("orders")
+	err := coll.DeleteIndex(context.Background(), "by_age")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package splunk
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DataPoint is a single value extracted from a search result row by
+// MetricValues, alongside the row's _time if the result had one.
+type DataPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// MetricOpts configures MetricValue and MetricValues.
+type MetricOpts struct {
+	// SavedSearch, if true, treats the query argument to MetricValue/
+	// MetricValues as a saved search name, dispatched via
+	// DispatchSavedSearch. Otherwise it's raw SPL, dispatched via
+	// CreateJob.
+	SavedSearch bool
+	// Namespace scopes saved search dispatch when SavedSearch is set;
+	// ignored otherwise.
+	Namespace Namespace
+
+	// ValueField names the result field to extract as the metric value.
+	// Defaults to "count".
+	ValueField string
+	// EarliestTime and LatestTime set the search's time window using
+	// Splunk time modifiers, e.g. "-5m", "now". Ignored when SavedSearch
+	// is set, since a saved search's time range is part of its
+	// definition.
+	EarliestTime string
+	LatestTime   string
+	// Timeout bounds how long to wait for the job to complete, including
+	// dispatch. Defaults to 30 seconds when zero.
+	Timeout time.Duration
+}
+
+// MetricValue dispatches query (per opts.SavedSearch, either raw SPL or
+// a saved search name) and extracts opts.ValueField from its first
+// result row as a float64. It's meant for Kubernetes custom-metrics-
+// style autoscaler integrations that poll Splunk for a single numeric
+// target.
+//
+// If ctx is canceled or opts.Timeout elapses before the job completes,
+// MetricValue finalizes the job so it doesn't keep running orphaned on
+// the Splunk side.
+func (c *Client) MetricValue(ctx context.Context, query string, opts MetricOpts) (float64, error) {
+	points, err := c.metricValues(ctx, query, opts, true)
+	if err != nil {
+		return 0, err
+	}
+	if len(points) == 0 {
+		return 0, fmt.Errorf("splunk: metric query returned no results")
+	}
+	return points[0].Value, nil
+}
+
+// MetricValues dispatches query the same way as MetricValue, but
+// extracts opts.ValueField from every result row instead of just the
+// first, for callers that want a time series rather than a single
+// value.
+func (c *Client) MetricValues(ctx context.Context, query string, opts MetricOpts) ([]DataPoint, error) {
+	return c.metricValues(ctx, query, opts, false)
+}
+
+func (c *Client) metricValues(ctx context.Context, query string, opts MetricOpts, firstOnly bool) ([]DataPoint, error) {
+	valueField := opts.ValueField
+	if valueField == "" {
+		valueField = "count"
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	job, err := c.dispatchMetricJob(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := job.Wait(ctx, searchPollInterval); err != nil {
+		// The job may still be running on the Splunk side if our wait was
+		// cut short by ctx/Timeout rather than the job itself failing;
+		// finalize it so it doesn't keep running orphaned on the Splunk
+		// side.
+		finalizeCtx, finalizeCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		_ = job.Finalize(finalizeCtx)
+		finalizeCancel()
+		return nil, fmt.Errorf("splunk: waiting for metric query job: %w", err)
+	}
+
+	count := 0
+	if firstOnly {
+		count = 1
+	}
+	results, err := job.Results(ctx, 0, count)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]DataPoint, 0, len(results.Rows))
+	for _, row := range results.Rows {
+		raw, ok := row[valueField]
+		if !ok {
+			return nil, fmt.Errorf("splunk: result row has no field %q", valueField)
+		}
+		value, err := toFloat64(raw)
+		if err != nil {
+			return nil, fmt.Errorf("splunk: parsing field %q: %w", valueField, err)
+		}
+
+		point := DataPoint{Value: value}
+		if rawTime, ok := row["_time"].(string); ok {
+			if t, err := time.Parse(time.RFC3339, rawTime); err == nil {
+				point.Time = t
+			}
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+func (c *Client) dispatchMetricJob(ctx context.Context, query string, opts MetricOpts) (*SearchJob, error) {
+	if opts.SavedSearch {
+		return c.DispatchSavedSearch(ctx, opts.Namespace, query, nil)
+	}
+	return c.CreateJob(ctx, query, JobOptions{
+		EarliestTime: opts.EarliestTime,
+		LatestTime:   opts.LatestTime,
+	})
+}
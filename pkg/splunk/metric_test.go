@@ -0,0 +1,116 @@
+package splunk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newMetricTestServer serves the job lifecycle (create, status, results)
+// that MetricValue/MetricValues drive regardless of whether the job came
+// from CreateJob or DispatchSavedSearch. The status endpoint always
+// reports the job done.
+func newMetricTestServer(t *testing.T, resultsBody string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/services/search/jobs" && r.Method == "POST":
+			io.WriteString(w, `<response><sid>12345</sid></response>`)
+		case strings.HasSuffix(r.URL.Path, "/results"):
+			io.WriteString(w, resultsBody)
+		case strings.HasSuffix(r.URL.Path, "/dispatch"):
+			io.WriteString(w, `{"sid":"12345"}`)
+		case strings.HasPrefix(r.URL.Path, "/services/search/jobs/"):
+			io.WriteString(w, `<entry><content><dict><key name="isDone">1</key></dict></content></entry>`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+}
+
+func TestMetricValue_PlainSPL(t *testing.T) {
+	server := newMetricTestServer(t, `{"fields":[{"name":"count"}],"results":[{"count":"7"}]}`)
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Token: "test-token", HTTPClient: server.Client()}
+	value, err := c.MetricValue(context.Background(), "search index=main | stats count", MetricOpts{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != 7 {
+		t.Errorf("expected 7, got %v", value)
+	}
+}
+
+func TestMetricValue_SavedSearch(t *testing.T) {
+	server := newMetricTestServer(t, `{"fields":[{"name":"count"}],"results":[{"count":"3"}]}`)
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Token: "test-token", HTTPClient: server.Client()}
+	value, err := c.MetricValue(context.Background(), "my-saved-search", MetricOpts{SavedSearch: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != 3 {
+		t.Errorf("expected 3, got %v", value)
+	}
+}
+
+func TestMetricValue_DefaultsValueFieldToCount(t *testing.T) {
+	server := newMetricTestServer(t, `{"fields":[{"name":"count"}],"results":[{"count":"9"}]}`)
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Token: "test-token", HTTPClient: server.Client()}
+	value, err := c.MetricValue(context.Background(), "search index=main", MetricOpts{ValueField: ""})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != 9 {
+		t.Errorf("expected 9, got %v", value)
+	}
+}
+
+func TestMetricValue_NoResults(t *testing.T) {
+	server := newMetricTestServer(t, `{"fields":[],"results":[]}`)
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Token: "test-token", HTTPClient: server.Client()}
+	_, err := c.MetricValue(context.Background(), "search index=main", MetricOpts{})
+	if err == nil || !strings.Contains(err.Error(), "no results") {
+		t.Fatalf("expected a no-results error, got %v", err)
+	}
+}
+
+func TestMetricValues_ExtractsEveryRow(t *testing.T) {
+	server := newMetricTestServer(t, `{"fields":[{"name":"cpu"},{"name":"_time"}],"results":[{"cpu":"1.5","_time":"2024-01-01T00:00:00Z"},{"cpu":"2.5","_time":"2024-01-01T00:01:00Z"}]}`)
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Token: "test-token", HTTPClient: server.Client()}
+	points, err := c.MetricValues(context.Background(), "search index=main", MetricOpts{ValueField: "cpu"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("expected 2 points, got %d", len(points))
+	}
+	if points[0].Value != 1.5 || points[1].Value != 2.5 {
+		t.Errorf("unexpected values: %+v", points)
+	}
+	if points[0].Time.IsZero() {
+		t.Errorf("expected the first point's time to be parsed from _time")
+	}
+}
+
+func TestMetricValues_MissingFieldErrors(t *testing.T) {
+	server := newMetricTestServer(t, `{"fields":[{"name":"count"}],"results":[{"count":"1"}]}`)
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Token: "test-token", HTTPClient: server.Client()}
+	_, err := c.MetricValues(context.Background(), "search index=main", MetricOpts{ValueField: "missing"})
+	if err == nil || !strings.Contains(err.Error(), `no field "missing"`) {
+		t.Fatalf("expected a missing-field error, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package splunk
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	// defaultMaxIdleConnsPerHost bounds the idle connection pool NewClient
+	// builds per host, sized for high-volume HEC ingestion rather than
+	// Go's conservative default of 2.
+	defaultMaxIdleConnsPerHost = 32
+	// defaultIdleConnTimeout closes pooled connections that have sat idle
+	// this long.
+	defaultIdleConnTimeout = 90 * time.Second
+)
+
+// Option configures a Client during construction. See WithCACertFile,
+// WithCACertPEM, WithInsecureSkipVerify, WithClientCertificate,
+// WithTimeout, and WithTransport.
+type Option func(*Client) error
+
+// transport returns the Client's *http.Transport, creating one with the
+// package's pooling defaults if the current Transport is not an
+// *http.Transport. A RoundTripper of another type, e.g. one set by an
+// earlier WithTransport option, is replaced.
+func (c *Client) transport() *http.Transport {
+	t, ok := c.HTTPClient.Transport.(*http.Transport)
+	if !ok {
+		t = &http.Transport{
+			MaxIdleConnsPerHost: defaultMaxIdleConnsPerHost,
+			IdleConnTimeout:     defaultIdleConnTimeout,
+		}
+		c.HTTPClient.Transport = t
+	}
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	return t
+}
+
+// WithCACertFile loads a PEM-encoded CA certificate bundle from path and
+// trusts it in addition to the system root CAs, for Splunk deployments
+// fronted by a private or self-signed CA.
+func WithCACertFile(path string) Option {
+	return func(c *Client) error {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read CA cert file: %w", err)
+		}
+		return WithCACertPEM(pem)(c)
+	}
+}
+
+// WithCACertPEM trusts the PEM-encoded CA certificate bundle in addition
+// to the system root CAs.
+func WithCACertPEM(pem []byte) Option {
+	return func(c *Client) error {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("failed to parse CA certificate PEM data")
+		}
+		c.transport().TLSClientConfig.RootCAs = pool
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify disables TLS certificate verification. Intended
+// for local development against a Splunk instance with a throwaway
+// self-signed certificate; do not use against production deployments.
+func WithInsecureSkipVerify(skip bool) Option {
+	return func(c *Client) error {
+		c.transport().TLSClientConfig.InsecureSkipVerify = skip
+		return nil
+	}
+}
+
+// WithClientCertificate configures mutual TLS by presenting the given
+// PEM-encoded certificate and key to the server, as required by some
+// Splunk Cloud and Splunk Enterprise deployments.
+func WithClientCertificate(certPath, keyPath string) Option {
+	return func(c *Client) error {
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig := c.transport().TLSClientConfig
+		tlsConfig.Certificates = append(tlsConfig.Certificates, cert)
+		return nil
+	}
+}
+
+// WithTimeout overrides the Client's default 30 second HTTP timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		c.HTTPClient.Timeout = d
+		return nil
+	}
+}
+
+// WithTransport overrides the Client's HTTP transport entirely, e.g. to
+// inject a tracing or metrics wrapping http.RoundTripper. TLS-related
+// options applied before it are discarded along with the transport they
+// configured. A TLS-related option applied after it configures rt when
+// rt is an *http.Transport, and otherwise replaces rt with a new
+// *http.Transport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) error {
+		c.HTTPClient.Transport = rt
+		return nil
+	}
+}
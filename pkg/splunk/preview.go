@@ -0,0 +1,197 @@
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// PreviewOptions configures StreamPreview.
+type PreviewOptions struct {
+	// PollInterval is used when the job does not stream results over a
+	// held-open connection (i.e. it isn't a realtime search); StreamPreview
+	// falls back to polling results_preview at this interval. Defaults to
+	// 2 seconds when zero.
+	PollInterval time.Duration
+	// MaxCount stops the preview after this many rows have been yielded.
+	// Zero means unbounded.
+	MaxCount int
+}
+
+// StreamPreview streams incremental preview rows for a running search job
+// via GET /services/search/jobs/{sid}/results_preview. For a realtime
+// search (search_mode=realtime), Splunk keeps the connection open and
+// appends results as they are indexed; StreamPreview decodes each
+// successive JSON document off that connection as it arrives. For a
+// non-realtime job, where the endpoint simply returns the current
+// snapshot and closes, StreamPreview falls back to polling the endpoint
+// at PreviewOptions.PollInterval.
+//
+// Both returned channels are closed when the server ends the stream, ctx
+// is canceled, or PreviewOptions.MaxCount rows have been yielded. Canceling
+// ctx closes the underlying response body to unblock any in-flight read.
+func (j *SearchJob) StreamPreview(ctx context.Context, opts PreviewOptions) (<-chan SearchRow, <-chan error) {
+	rowCh := make(chan SearchRow)
+	errCh := make(chan error, 1)
+
+	pollInterval := opts.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = 2 * time.Second
+	}
+
+	go func() {
+		defer close(rowCh)
+		defer close(errCh)
+
+		sent := 0
+		emit := func(row SearchRow) bool {
+			select {
+			case rowCh <- row:
+				sent++
+				return opts.MaxCount <= 0 || sent < opts.MaxCount
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		more, err := j.streamPreviewConnection(ctx, emit)
+		if err != nil {
+			errCh <- err
+			return
+		}
+		if !more {
+			return
+		}
+
+		// The connection closed without streaming further rows: this is a
+		// non-realtime job. Fall back to polling for new rows.
+		offset := sent
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pollInterval):
+			}
+
+			page, err := j.fetchPreviewPage(ctx, offset, 0)
+			if err != nil {
+				errCh <- err
+				return
+			}
+			for _, row := range page.Rows {
+				if !emit(row) {
+					return
+				}
+			}
+			offset += len(page.Rows)
+
+			status, err := j.Status(ctx)
+			if err != nil {
+				errCh <- err
+				return
+			}
+			if status.IsDone && len(page.Rows) == 0 {
+				return
+			}
+		}
+	}()
+
+	return rowCh, errCh
+}
+
+// streamPreviewConnection holds open a GET to results_preview and decodes
+// consecutive JSON documents off it, emitting each row via emit. It
+// returns more=true if the connection closed without emit ever returning
+// false, meaning the caller should fall back to polling.
+func (j *SearchJob) streamPreviewConnection(ctx context.Context, emit func(SearchRow) bool) (more bool, err error) {
+	headers, err := j.client.authHeaders(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	previewURL := fmt.Sprintf("%s/services/search/jobs/%s/results_preview?output_mode=json", j.client.BaseURL, j.SID)
+	req, err := http.NewRequestWithContext(ctx, "GET", previewURL, nil)
+	if err != nil {
+		return false, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := j.client.HTTPClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	// Closing the body unblocks any in-flight Read as soon as ctx is
+	// canceled, since the decode loop below has no other way to notice.
+	closed := make(chan struct{})
+	defer close(closed)
+	go func() {
+		select {
+		case <-ctx.Done():
+			resp.Body.Close()
+		case <-closed:
+		}
+	}()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("results_preview request failed: %s - %s", resp.Status, string(body))
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var page resultsResponse
+		if decodeErr := decoder.Decode(&page); decodeErr != nil {
+			if decodeErr == io.EOF {
+				return true, nil
+			}
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			return true, nil
+		}
+		for _, row := range page.Results {
+			if !emit(row) {
+				return false, nil
+			}
+		}
+	}
+}
+
+// fetchPreviewPage fetches a single snapshot of results_preview, used by
+// StreamPreview's polling fallback.
+func (j *SearchJob) fetchPreviewPage(ctx context.Context, offset, count int) (*SearchResults, error) {
+	headers, err := j.client.authHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	previewURL := fmt.Sprintf("%s/services/search/jobs/%s/results_preview?output_mode=json&offset=%d&count=%d",
+		j.client.BaseURL, j.SID, offset, count)
+	resp, err := j.client.doRequest(ctx, "GET", previewURL, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("results_preview request failed: %s", resp.Status)
+	}
+
+	var parsed resultsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse preview results: %w", err)
+	}
+
+	fields := make([]string, len(parsed.Fields))
+	for i, f := range parsed.Fields {
+		fields[i] = f.Name
+	}
+	return &SearchResults{Fields: fields, Rows: parsed.Results}, nil
+}
@@ -0,0 +1,532 @@
+package splunk
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// ResultMessage is a Splunk info/warning/error message interleaved with
+// search results, e.g. a field extraction warning or a truncated-results
+// notice. Splunk emits these alongside rows rather than as errors, so
+// they are delivered separately from ResultReader.Err.
+type ResultMessage struct {
+	Type string
+	Text string
+}
+
+// ResultReader decodes a search results payload in any of Splunk's
+// output_mode values (json, json_rows, json_cols, csv, xml) into a
+// uniform stream of SearchRow events, reading incrementally off body
+// rather than buffering the full result set in memory. json, csv, and
+// xml stream one row at a time as the underlying decoder advances;
+// json_cols must buffer its column arrays before the first row can be
+// produced, since Splunk emits columns only after every row's worth of
+// data is complete.
+//
+// Call Next to advance, Row or Decode to read the current row, and Err
+// once Next returns false to check for a decode error. Messages
+// receives any ResultMessage values found in the payload through a
+// 16-message buffer; decoding never blocks on it, and messages found
+// while the buffer is full are dropped.
+type ResultReader struct {
+	rowCh chan SearchRow
+	msgCh chan ResultMessage
+	done  chan struct{}
+	body  io.Closer
+
+	closeOnce sync.Once
+	current   SearchRow
+	err       error
+}
+
+// NewResultReader starts decoding body according to outputMode, which
+// should match the output_mode query parameter the request was made
+// with ("" is treated as "json"). body is closed when the reader is
+// exhausted or Close is called.
+func NewResultReader(body io.ReadCloser, outputMode string) *ResultReader {
+	r := &ResultReader{
+		rowCh: make(chan SearchRow),
+		msgCh: make(chan ResultMessage, 16),
+		done:  make(chan struct{}),
+		body:  body,
+	}
+	go r.run(body, outputMode)
+	return r
+}
+
+func (r *ResultReader) run(body io.ReadCloser, outputMode string) {
+	defer close(r.rowCh)
+	defer close(r.msgCh)
+	defer body.Close()
+
+	switch outputMode {
+	case "", "json":
+		r.err = r.decodeJSON(body)
+	case "json_rows":
+		r.err = r.decodeJSONRows(body)
+	case "json_cols":
+		r.err = r.decodeJSONCols(body)
+	case "csv":
+		r.err = r.decodeCSV(body)
+	case "xml":
+		r.err = r.decodeXML(body)
+	default:
+		r.err = fmt.Errorf("splunk: unsupported output_mode %q", outputMode)
+	}
+}
+
+func (r *ResultReader) emit(row SearchRow) {
+	select {
+	case r.rowCh <- row:
+	case <-r.done:
+	}
+}
+
+func (r *ResultReader) emitMessage(m ResultMessage) {
+	select {
+	case r.msgCh <- m:
+	default:
+		// Messages is a best-effort side channel: a caller not reading it
+		// shouldn't stall result decoding.
+	}
+}
+
+// Next advances the reader to the next row, returning false once the
+// result set is exhausted or a decode error occurs; check Err in the
+// latter case.
+func (r *ResultReader) Next() bool {
+	row, ok := <-r.rowCh
+	if !ok {
+		return false
+	}
+	r.current = row
+	return true
+}
+
+// Row returns the row most recently loaded by Next.
+func (r *ResultReader) Row() SearchRow {
+	return r.current
+}
+
+// Messages receives ResultMessage values as they're found in the
+// payload. It is closed once decoding finishes.
+func (r *ResultReader) Messages() <-chan ResultMessage {
+	return r.msgCh
+}
+
+// Err returns the first error encountered while decoding, if any. It is
+// only meaningful after Next has returned false.
+func (r *ResultReader) Err() error {
+	return r.err
+}
+
+// Close signals the decode goroutine to stop and releases the
+// underlying response body, unblocking any in-flight read or row send.
+// Callers that consume the reader to exhaustion via Next need not call
+// Close; it's only required when abandoning the reader early.
+func (r *ResultReader) Close() error {
+	r.closeOnce.Do(func() { close(r.done) })
+	return r.body.Close()
+}
+
+// Decode copies the current row (as set by the most recent call to
+// Next) into dest, a pointer to a struct. Fields are matched against row
+// keys using the struct's `splunk` tag, falling back to the field name
+// when the tag is absent; a tag of "-" skips the field.
+func (r *ResultReader) Decode(dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("splunk: Decode requires a non-nil pointer to a struct")
+	}
+
+	elem := v.Elem()
+	t := elem.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		tag := field.Tag.Get("splunk")
+		if tag == "-" {
+			continue
+		}
+		key := tag
+		if key == "" {
+			key = field.Name
+		}
+		raw, ok := r.current[key]
+		if !ok {
+			continue
+		}
+		if err := setFieldValue(elem.Field(i), raw); err != nil {
+			return fmt.Errorf("splunk: decoding field %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func setFieldValue(field reflect.Value, raw any) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(fmt.Sprint(raw))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := toInt64(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat64(raw)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := toBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	default:
+		val := reflect.ValueOf(raw)
+		if val.IsValid() && val.Type().AssignableTo(field.Type()) {
+			field.Set(val)
+			return nil
+		}
+		return fmt.Errorf("unsupported field kind %s for value %v", field.Kind(), raw)
+	}
+	return nil
+}
+
+func toInt64(raw any) (int64, error) {
+	switch v := raw.(type) {
+	case float64:
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("cannot convert %T to int64", raw)
+	}
+}
+
+func toFloat64(raw any) (float64, error) {
+	switch v := raw.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("cannot convert %T to float64", raw)
+	}
+}
+
+func toBool(raw any) (bool, error) {
+	switch v := raw.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(v)
+	default:
+		return false, fmt.Errorf("cannot convert %T to bool", raw)
+	}
+}
+
+// decodeJSON streams output_mode=json, whose top-level object holds
+// "fields", "messages", and "results" keys. Only "results" and
+// "messages" are streamed element-by-element; other keys are skipped.
+func (r *ResultReader) decodeJSON(body io.Reader) error {
+	dec := json.NewDecoder(body)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		key, err := nextObjectKey(dec)
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "results":
+			if err := decodeJSONArray(dec, func(raw json.RawMessage) error {
+				var row SearchRow
+				if err := json.Unmarshal(raw, &row); err != nil {
+					return err
+				}
+				r.emit(row)
+				return nil
+			}); err != nil {
+				return err
+			}
+		case "messages":
+			if err := decodeJSONMessages(dec, r.emitMessage); err != nil {
+				return err
+			}
+		default:
+			var discard json.RawMessage
+			if err := dec.Decode(&discard); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := dec.Token() // closing brace
+	return err
+}
+
+// decodeJSONRows streams output_mode=json_rows, whose top-level object
+// holds "fields" (column names) and "rows" (each a positional array of
+// values). fields must arrive before rows to zip them into SearchRows,
+// which matches the order Splunk emits them in.
+func (r *ResultReader) decodeJSONRows(body io.Reader) error {
+	dec := json.NewDecoder(body)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	var fields []string
+	for dec.More() {
+		key, err := nextObjectKey(dec)
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "fields":
+			if err := dec.Decode(&fields); err != nil {
+				return err
+			}
+		case "rows":
+			if err := decodeJSONArray(dec, func(raw json.RawMessage) error {
+				var vals []any
+				if err := json.Unmarshal(raw, &vals); err != nil {
+					return err
+				}
+				row := make(SearchRow, len(vals))
+				for i, v := range vals {
+					if i < len(fields) {
+						row[fields[i]] = v
+					}
+				}
+				r.emit(row)
+				return nil
+			}); err != nil {
+				return err
+			}
+		case "messages":
+			if err := decodeJSONMessages(dec, r.emitMessage); err != nil {
+				return err
+			}
+		default:
+			var discard json.RawMessage
+			if err := dec.Decode(&discard); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := dec.Token()
+	return err
+}
+
+// decodeJSONCols parses output_mode=json_cols, whose top-level object
+// holds "fields" and "columns" (one array per field, each holding that
+// field's value for every row). Splunk emits each column array in full
+// before the next, so unlike the other JSON modes this can't produce a
+// row until every column has been read.
+func (r *ResultReader) decodeJSONCols(body io.Reader) error {
+	dec := json.NewDecoder(body)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	var fields []string
+	var columns [][]any
+	for dec.More() {
+		key, err := nextObjectKey(dec)
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "fields":
+			if err := dec.Decode(&fields); err != nil {
+				return err
+			}
+		case "columns":
+			if err := dec.Decode(&columns); err != nil {
+				return err
+			}
+		case "messages":
+			if err := decodeJSONMessages(dec, r.emitMessage); err != nil {
+				return err
+			}
+		default:
+			var discard json.RawMessage
+			if err := dec.Decode(&discard); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	rowCount := 0
+	for _, col := range columns {
+		if len(col) > rowCount {
+			rowCount = len(col)
+		}
+	}
+	for i := 0; i < rowCount; i++ {
+		row := make(SearchRow, len(columns))
+		for c, col := range columns {
+			if c < len(fields) && i < len(col) {
+				row[fields[c]] = col[i]
+			}
+		}
+		r.emit(row)
+	}
+	return nil
+}
+
+// decodeCSV streams output_mode=csv, reading the header row for field
+// names and then one data row at a time.
+func (r *ResultReader) decodeCSV(body io.Reader) error {
+	cr := csv.NewReader(body)
+	header, err := cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		row := make(SearchRow, len(header))
+		for i, h := range header {
+			if i < len(rec) {
+				row[h] = rec[i]
+			}
+		}
+		r.emit(row)
+	}
+}
+
+// xmlResultField is a single <field k="name"><value><text>...</text>
+// </value></field> element within a <result>.
+type xmlResultField struct {
+	Name  string `xml:"k,attr"`
+	Value struct {
+		Text string `xml:"text"`
+	} `xml:"value"`
+}
+
+// decodeXML streams output_mode=xml, emitting a row for each <result>
+// element and a message for each <msg type="..."> element as they're
+// encountered, in whatever order Splunk interleaves them.
+func (r *ResultReader) decodeXML(body io.Reader) error {
+	dec := xml.NewDecoder(body)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch se.Name.Local {
+		case "result":
+			var parsed struct {
+				Fields []xmlResultField `xml:"field"`
+			}
+			if err := dec.DecodeElement(&parsed, &se); err != nil {
+				return err
+			}
+			row := make(SearchRow, len(parsed.Fields))
+			for _, f := range parsed.Fields {
+				row[f.Name] = f.Value.Text
+			}
+			r.emit(row)
+		case "msg":
+			var parsed struct {
+				Type string `xml:"type,attr"`
+				Text string `xml:",chardata"`
+			}
+			if err := dec.DecodeElement(&parsed, &se); err != nil {
+				return err
+			}
+			r.emitMessage(ResultMessage{Type: parsed.Type, Text: parsed.Text})
+		}
+	}
+}
+
+// expectDelim consumes the next JSON token and errors unless it's the
+// given delimiter.
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("splunk: expected JSON %q, got %v", want, tok)
+	}
+	return nil
+}
+
+// nextObjectKey reads the next object key token as a string.
+func nextObjectKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("splunk: expected JSON object key, got %v", tok)
+	}
+	return key, nil
+}
+
+// decodeJSONArray streams the array at the decoder's current position,
+// invoking fn with each element's raw JSON.
+func decodeJSONArray(dec *json.Decoder, fn func(json.RawMessage) error) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		if err := fn(raw); err != nil {
+			return err
+		}
+	}
+	_, err := dec.Token() // closing bracket
+	return err
+}
+
+// decodeJSONMessages streams a "messages" array, whose elements are
+// {"type": "...", "text": "..."} objects.
+func decodeJSONMessages(dec *json.Decoder, emit func(ResultMessage)) error {
+	return decodeJSONArray(dec, func(raw json.RawMessage) error {
+		var parsed struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(raw, &parsed); err != nil {
+			return err
+		}
+		emit(ResultMessage{Type: parsed.Type, Text: parsed.Text})
+		return nil
+	})
+}
@@ -0,0 +1,198 @@
+package splunk
+
+import (
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readAllRows(t *testing.T, r *ResultReader) []SearchRow {
+	t.Helper()
+	var rows []SearchRow
+	for r.Next() {
+		rows = append(rows, r.Row())
+	}
+	if err := r.Err(); err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+	return rows
+}
+
+func TestResultReader_JSON(t *testing.T) {
+	body := `{"fields":[{"name":"x"}],"messages":[{"type":"INFO","text":"hello"}],"results":[{"x":"1"},{"x":"2"}]}`
+	r := NewResultReader(io.NopCloser(strings.NewReader(body)), "json")
+
+	rows := readAllRows(t, r)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0]["x"] != "1" || rows[1]["x"] != "2" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+
+	select {
+	case msg, ok := <-r.Messages():
+		if !ok {
+			t.Fatalf("expected a message, channel closed")
+		}
+		if msg.Type != "INFO" || msg.Text != "hello" {
+			t.Errorf("unexpected message: %+v", msg)
+		}
+	default:
+		t.Fatalf("expected a message to be available")
+	}
+}
+
+func TestResultReader_JSONRows(t *testing.T) {
+	body := `{"fields":["x","y"],"rows":[["1","2"],["3","4"]]}`
+	r := NewResultReader(io.NopCloser(strings.NewReader(body)), "json_rows")
+
+	rows := readAllRows(t, r)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0]["x"] != "1" || rows[0]["y"] != "2" {
+		t.Errorf("unexpected first row: %v", rows[0])
+	}
+	if rows[1]["x"] != "3" || rows[1]["y"] != "4" {
+		t.Errorf("unexpected second row: %v", rows[1])
+	}
+}
+
+func TestResultReader_JSONCols(t *testing.T) {
+	body := `{"fields":["x","y"],"columns":[["1","3"],["2","4"]]}`
+	r := NewResultReader(io.NopCloser(strings.NewReader(body)), "json_cols")
+
+	rows := readAllRows(t, r)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0]["x"] != "1" || rows[0]["y"] != "2" {
+		t.Errorf("unexpected first row: %v", rows[0])
+	}
+	if rows[1]["x"] != "3" || rows[1]["y"] != "4" {
+		t.Errorf("unexpected second row: %v", rows[1])
+	}
+}
+
+func TestResultReader_CSV(t *testing.T) {
+	body := "x,y\n1,2\n3,4\n"
+	r := NewResultReader(io.NopCloser(strings.NewReader(body)), "csv")
+
+	rows := readAllRows(t, r)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0]["x"] != "1" || rows[0]["y"] != "2" {
+		t.Errorf("unexpected first row: %v", rows[0])
+	}
+}
+
+func TestResultReader_XML(t *testing.T) {
+	body := `<results>
+		<result><field k="x"><value><text>1</text></value></field></result>
+		<msg type="WARN">careful</msg>
+		<result><field k="x"><value><text>2</text></value></field></result>
+	</results>`
+	r := NewResultReader(io.NopCloser(strings.NewReader(body)), "xml")
+
+	rows := readAllRows(t, r)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0]["x"] != "1" || rows[1]["x"] != "2" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+
+	msg, ok := <-r.Messages()
+	if !ok || msg.Type != "WARN" || msg.Text != "careful" {
+		t.Errorf("unexpected message: %+v (ok=%v)", msg, ok)
+	}
+}
+
+func TestResultReader_UnsupportedOutputMode(t *testing.T) {
+	r := NewResultReader(io.NopCloser(strings.NewReader(`{}`)), "bogus")
+	if r.Next() {
+		t.Fatalf("expected no rows for an unsupported output_mode")
+	}
+	if r.Err() == nil {
+		t.Fatalf("expected an error for an unsupported output_mode")
+	}
+}
+
+func TestResultReader_Decode(t *testing.T) {
+	body := `{"results":[{"_time":"2024-01-01T00:00:00Z","count":"42","host":"web1","active":"true"}]}`
+	r := NewResultReader(io.NopCloser(strings.NewReader(body)), "json")
+
+	if !r.Next() {
+		t.Fatalf("expected a row, got none (err=%v)", r.Err())
+	}
+
+	var dest struct {
+		Time   string `splunk:"_time"`
+		Count  int    `splunk:"count"`
+		Host   string `splunk:"host"`
+		Active bool   `splunk:"active"`
+		Ignore string `splunk:"-"`
+	}
+	if err := r.Decode(&dest); err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+	if dest.Time != "2024-01-01T00:00:00Z" {
+		t.Errorf("unexpected Time: %q", dest.Time)
+	}
+	if dest.Count != 42 {
+		t.Errorf("unexpected Count: %d", dest.Count)
+	}
+	if dest.Host != "web1" {
+		t.Errorf("unexpected Host: %q", dest.Host)
+	}
+	if !dest.Active {
+		t.Errorf("expected Active to be true")
+	}
+	if dest.Ignore != "" {
+		t.Errorf("expected Ignore to be left untouched, got %q", dest.Ignore)
+	}
+}
+
+func TestResultReader_DecodeRequiresStructPointer(t *testing.T) {
+	r := NewResultReader(io.NopCloser(strings.NewReader(`{"results":[{"x":"1"}]}`)), "json")
+	if !r.Next() {
+		t.Fatalf("expected a row, got none (err=%v)", r.Err())
+	}
+
+	var notAPointer struct{ X string }
+	if err := r.Decode(notAPointer); err == nil {
+		t.Fatalf("expected an error decoding into a non-pointer")
+	}
+}
+
+// TestResultReader_CloseUnblocksDecodeGoroutine guards against the decode
+// goroutine leaking when a caller abandons the reader before exhausting
+// it: without Close selecting on a done signal, the goroutine parks
+// forever on an unbuffered send to rowCh.
+func TestResultReader_CloseUnblocksDecodeGoroutine(t *testing.T) {
+	body := `{"results":[{"x":"1"},{"x":"2"},{"x":"3"}]}`
+	before := runtime.NumGoroutine()
+
+	r := NewResultReader(io.NopCloser(strings.NewReader(body)), "json")
+	if !r.Next() {
+		t.Fatalf("expected a row, got none (err=%v)", r.Err())
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if runtime.NumGoroutine() <= before {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("decode goroutine still running %s after Close", time.Second)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,184 @@
+package splunk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how Search, SendEvents, and the job-lifecycle
+// methods retry failed requests. Install one with WithRetry.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// RetryHook is called after every attempt, including the first, so callers
+// can log or emit metrics for each retry. resp is nil if the attempt
+// failed at the transport level rather than with an HTTP response.
+type RetryHook func(attempt int, err error, resp *http.Response)
+
+// WithRetry installs a retry policy that wraps every outbound request the
+// Client makes. Requests are retried on transport errors, HTTP 5xx, and
+// HTTP 429, honoring a Retry-After response header when present and
+// otherwise backing off exponentially with full jitter:
+// min(max, base*2^attempt) scaled by rand.Int63n.
+func WithRetry(maxAttempts int, base, max time.Duration) Option {
+	return func(c *Client) error {
+		c.retryPolicy = &RetryPolicy{MaxAttempts: maxAttempts, BaseDelay: base, MaxDelay: max}
+		return nil
+	}
+}
+
+// WithRetryHook installs a RetryHook, invoked after each attempt the
+// Client makes. Without WithRetry every request makes a single attempt,
+// so the hook is called once per request.
+func WithRetryHook(hook RetryHook) Option {
+	return func(c *Client) error {
+		c.RetryHook = hook
+		return nil
+	}
+}
+
+// isRetryableStatus reports whether an HTTP response status should be
+// retried: 429, any 5xx, including the HEC-specific 503 "Server is busy"
+// transient response.
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || (statusCode >= 500 && statusCode <= 599)
+}
+
+// parseRetryAfter parses a Retry-After header in either delta-seconds or
+// HTTP-date form, returning zero if the header is absent or unparseable.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// backoffDelay computes an exponential backoff delay with full jitter:
+// a random duration in [0, min(max, base*2^attempt)).
+func backoffDelay(base, max time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	delay := base
+	for i := 0; i < attempt; i++ {
+		delay *= 2
+		if delay > max || delay <= 0 {
+			delay = max
+			break
+		}
+	}
+	if delay > max {
+		delay = max
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay)))
+}
+
+// doRequest issues an HTTP request, retrying according to the Client's
+// RetryPolicy when one is installed. bodyFn, if non-nil, is invoked fresh
+// for every attempt so the request body is replayable even though
+// io.Reader bodies are consumed on send. headers are applied to every
+// attempt's request; if the installed Authenticator supports it, a 401
+// response invalidates and re-derives the auth header once, independent
+// of and before any RetryPolicy-governed retries.
+func (c *Client) doRequest(ctx context.Context, method, url string, headers map[string]string, bodyFn func() io.Reader) (*http.Response, error) {
+	maxAttempts := 1
+	policy := c.retryPolicy
+	if policy != nil && policy.MaxAttempts > maxAttempts {
+		maxAttempts = policy.MaxAttempts
+	}
+
+	reauthed := false
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var body io.Reader
+		if bodyFn != nil {
+			body = bodyFn()
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, body)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && !reauthed {
+			if ra, ok := c.auth.(reauthenticator); ok {
+				reauthed = true
+				ra.Invalidate()
+				resp.Body.Close()
+				if refreshed, rerr := c.authHeaders(ctx); rerr == nil {
+					for k, v := range refreshed {
+						headers[k] = v
+					}
+				}
+				attempt--
+				continue
+			}
+		}
+
+		retryable := false
+		var attemptErr error
+		switch {
+		case err != nil:
+			attemptErr = err
+			retryable = true
+		case isRetryableStatus(resp.StatusCode):
+			attemptErr = fmt.Errorf("request failed: %s", resp.Status)
+			retryable = true
+		}
+		if attemptErr != nil {
+			lastErr = attemptErr
+		}
+
+		if c.RetryHook != nil {
+			c.RetryHook(attempt, attemptErr, resp)
+		}
+
+		if !retryable || policy == nil || attempt == maxAttempts-1 {
+			return resp, err
+		}
+
+		var retryAfter time.Duration
+		if resp != nil {
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			resp.Body.Close()
+		}
+		delay := retryAfter
+		if delay == 0 {
+			delay = backoffDelay(policy.BaseDelay, policy.MaxDelay, attempt)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	return nil, lastErr
+}
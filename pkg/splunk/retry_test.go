@@ -0,0 +1,191 @@
+package splunk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDoRequest_RetriesOnServerError(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := &Client{
+		HTTPClient:  server.Client(),
+		retryPolicy: &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond},
+	}
+
+	resp, err := c.doRequest(context.Background(), "GET", server.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestDoRequest_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := &Client{
+		HTTPClient:  server.Client(),
+		retryPolicy: &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond},
+	}
+
+	resp, err := c.doRequest(context.Background(), "GET", server.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected transport error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the final failing response to be returned, got %d", resp.StatusCode)
+	}
+	if calls != 2 {
+		t.Errorf("expected exactly 2 attempts, got %d", calls)
+	}
+}
+
+func TestDoRequest_HonorsRetryAfter(t *testing.T) {
+	var calls int
+	var firstAttempt, secondAttempt time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			firstAttempt = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		secondAttempt = time.Now()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := &Client{
+		HTTPClient: server.Client(),
+		// BaseDelay is deliberately tiny so a pass only happens if
+		// Retry-After (1s), not the backoff policy, is what's honored.
+		retryPolicy: &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	}
+
+	resp, err := c.doRequest(context.Background(), "GET", server.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if gap := secondAttempt.Sub(firstAttempt); gap < 900*time.Millisecond {
+		t.Errorf("expected the retry to wait out Retry-After (~1s), only waited %s", gap)
+	}
+}
+
+func TestDoRequest_ReauthenticatesOnce(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	auth := &countingAuth{}
+	c := &Client{HTTPClient: server.Client(), auth: auth}
+
+	headers, err := c.authHeaders(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := c.doRequest(context.Background(), "GET", server.URL, headers, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the retried request to succeed, got %d", resp.StatusCode)
+	}
+	if calls != 2 {
+		t.Errorf("expected exactly 2 attempts (stale, then refreshed), got %d", calls)
+	}
+	if auth.invalidated != 1 {
+		t.Errorf("expected Invalidate to be called exactly once, got %d", auth.invalidated)
+	}
+}
+
+// countingAuth issues a stale token until Invalidate is called, then a
+// fresh one, so TestDoRequest_ReauthenticatesOnce can tell whether
+// doRequest actually re-derived the header after a 401.
+type countingAuth struct {
+	invalidated int
+}
+
+func (a *countingAuth) Authenticate(_ context.Context, _ *Client, req *http.Request) error {
+	if a.invalidated == 0 {
+		req.Header.Set("Authorization", "Bearer stale")
+	} else {
+		req.Header.Set("Authorization", "Bearer fresh")
+	}
+	return nil
+}
+
+func (a *countingAuth) Invalidate() {
+	a.invalidated++
+}
+
+func TestParseRetryAfter_DeltaSeconds(t *testing.T) {
+	d := parseRetryAfter("5")
+	if d != 5*time.Second {
+		t.Errorf("expected 5s, got %s", d)
+	}
+}
+
+func TestParseRetryAfter_Empty(t *testing.T) {
+	if d := parseRetryAfter(""); d != 0 {
+		t.Errorf("expected 0, got %s", d)
+	}
+}
+
+func TestBackoffDelay_BoundedByMax(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		d := backoffDelay(time.Millisecond, 10*time.Millisecond, attempt)
+		if d > 10*time.Millisecond {
+			t.Errorf("attempt %d: delay %s exceeded max", attempt, d)
+		}
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	cases := map[int]bool{
+		http.StatusOK:                  false,
+		http.StatusBadRequest:          false,
+		http.StatusTooManyRequests:     true,
+		http.StatusInternalServerError: true,
+		http.StatusServiceUnavailable:  true,
+	}
+	for status, want := range cases {
+		if got := isRetryableStatus(status); got != want {
+			t.Errorf("isRetryableStatus(%s): got %v, want %v", strconv.Itoa(status), got, want)
+		}
+	}
+}
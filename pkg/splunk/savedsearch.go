@@ -0,0 +1,360 @@
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Namespace scopes a saved search to a Splunk app/user context, used to
+// build /servicesNS/{user}/{app}/... paths. An empty User or App falls
+// back to "-", Splunk's wildcard for "any".
+type Namespace struct {
+	User string
+	App  string
+}
+
+func (ns Namespace) path() string {
+	user := ns.User
+	if user == "" {
+		user = "-"
+	}
+	app := ns.App
+	if app == "" {
+		app = "-"
+	}
+	return fmt.Sprintf("/servicesNS/%s/%s", url.PathEscape(user), url.PathEscape(app))
+}
+
+// AlertAction configures a notification Splunk sends when a saved search's
+// alert condition is met: email, a webhook, or a script.
+type AlertAction struct {
+	Email   string // recipient address, for the "email" action
+	Webhook string // target URL, for the "webhook" action
+	Script  string // script filename under $SPLUNK_HOME/bin/scripts, for the "script" action
+}
+
+// SavedSearchSpec describes a saved search and its optional alerting
+// configuration.
+type SavedSearchSpec struct {
+	Name   string
+	Search string
+
+	// CronSchedule schedules the search, e.g. "*/5 * * * *". Leave empty
+	// for an unscheduled, manually-dispatched saved search.
+	CronSchedule string
+
+	// AlertCondition is a search string Splunk evaluates against the
+	// results to decide whether to fire the alert, e.g.
+	// "search count > 0".
+	AlertCondition string
+	// AlertThreshold sets alert_threshold for the built-in number-of-events
+	// comparisons (used together with AlertComparator).
+	AlertThreshold string
+	// AlertComparator is one of "greater than", "less than", "equal to",
+	// "not equal to", "drops by", "rises by".
+	AlertComparator string
+
+	Actions []AlertAction
+}
+
+// savedSearchEntry models the subset of a /saved/searches JSON entry that
+// SavedSearchSpec cares about.
+type savedSearchEntry struct {
+	Name    string `json:"name"`
+	Content struct {
+		Search               string `json:"search"`
+		CronSchedule         string `json:"cron_schedule"`
+		AlertCondition       string `json:"alert_condition"`
+		AlertThreshold       string `json:"alert_threshold"`
+		AlertComparator      string `json:"alert_comparator"`
+		ActionEmail          string `json:"action.email"`
+		ActionEmailTo        string `json:"action.email.to"`
+		ActionWebhook        string `json:"action.webhook"`
+		ActionWebhookURL     string `json:"action.webhook.url"`
+		ActionScript         string `json:"action.script"`
+		ActionScriptFilename string `json:"action.script.filename"`
+	} `json:"content"`
+}
+
+func (e *savedSearchEntry) toSpec() SavedSearchSpec {
+	spec := SavedSearchSpec{
+		Name:            e.Name,
+		Search:          e.Content.Search,
+		CronSchedule:    e.Content.CronSchedule,
+		AlertCondition:  e.Content.AlertCondition,
+		AlertThreshold:  e.Content.AlertThreshold,
+		AlertComparator: e.Content.AlertComparator,
+	}
+	if e.Content.ActionEmail == "1" {
+		spec.Actions = append(spec.Actions, AlertAction{Email: e.Content.ActionEmailTo})
+	}
+	if e.Content.ActionWebhook == "1" {
+		spec.Actions = append(spec.Actions, AlertAction{Webhook: e.Content.ActionWebhookURL})
+	}
+	if e.Content.ActionScript == "1" {
+		spec.Actions = append(spec.Actions, AlertAction{Script: e.Content.ActionScriptFilename})
+	}
+	return spec
+}
+
+// formValues renders a SavedSearchSpec as the form-encoded body Splunk's
+// saved/searches endpoints expect.
+func (spec SavedSearchSpec) formValues() url.Values {
+	values := url.Values{}
+	values.Set("name", spec.Name)
+	values.Set("search", spec.Search)
+	if spec.CronSchedule != "" {
+		values.Set("cron_schedule", spec.CronSchedule)
+		values.Set("is_scheduled", "1")
+	}
+	if spec.AlertCondition != "" {
+		values.Set("alert_condition", spec.AlertCondition)
+	}
+	if spec.AlertThreshold != "" {
+		values.Set("alert_threshold", spec.AlertThreshold)
+	}
+	if spec.AlertComparator != "" {
+		values.Set("alert_comparator", spec.AlertComparator)
+	}
+	for _, action := range spec.Actions {
+		switch {
+		case action.Email != "":
+			values.Set("action.email", "1")
+			values.Set("action.email.to", action.Email)
+		case action.Webhook != "":
+			values.Set("action.webhook", "1")
+			values.Set("action.webhook.url", action.Webhook)
+		case action.Script != "":
+			values.Set("action.script", "1")
+			values.Set("action.script.filename", action.Script)
+		}
+	}
+	return values
+}
+
+// CreateSavedSearch creates a new saved search in the given namespace.
+func (c *Client) CreateSavedSearch(ctx context.Context, ns Namespace, spec SavedSearchSpec) error {
+	headers, err := c.authHeaders(ctx)
+	if err != nil {
+		return err
+	}
+	headers["Content-Type"] = "application/x-www-form-urlencoded"
+
+	body := spec.formValues().Encode()
+	searchesURL := c.BaseURL + ns.path() + "/saved/searches"
+	resp, err := c.doRequest(ctx, "POST", searchesURL, headers, func() io.Reader { return strings.NewReader(body) })
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("create saved search failed: %s - %s", resp.Status, string(respBody))
+	}
+	return nil
+}
+
+// GetSavedSearch fetches a single saved search by name.
+func (c *Client) GetSavedSearch(ctx context.Context, ns Namespace, name string) (*SavedSearchSpec, error) {
+	headers, err := c.authHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	searchURL := fmt.Sprintf("%s%s/saved/searches/%s?output_mode=json", c.BaseURL, ns.path(), url.PathEscape(name))
+	resp, err := c.doRequest(ctx, "GET", searchURL, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get saved search failed: %s", resp.Status)
+	}
+
+	var parsed struct {
+		Entry []savedSearchEntry `json:"entry"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse saved search: %w", err)
+	}
+	if len(parsed.Entry) == 0 {
+		return nil, fmt.Errorf("saved search %q not found", name)
+	}
+	spec := parsed.Entry[0].toSpec()
+	return &spec, nil
+}
+
+// UpdateSavedSearch updates an existing saved search's properties. Only
+// non-empty fields of spec are sent.
+func (c *Client) UpdateSavedSearch(ctx context.Context, ns Namespace, name string, spec SavedSearchSpec) error {
+	headers, err := c.authHeaders(ctx)
+	if err != nil {
+		return err
+	}
+	headers["Content-Type"] = "application/x-www-form-urlencoded"
+
+	values := spec.formValues()
+	values.Del("name") // the name is part of the URL, not an updatable field
+	if spec.Search == "" {
+		values.Del("search")
+	}
+	body := values.Encode()
+
+	searchURL := fmt.Sprintf("%s%s/saved/searches/%s", c.BaseURL, ns.path(), url.PathEscape(name))
+	resp, err := c.doRequest(ctx, "POST", searchURL, headers, func() io.Reader { return strings.NewReader(body) })
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("update saved search failed: %s - %s", resp.Status, string(respBody))
+	}
+	return nil
+}
+
+// DeleteSavedSearch removes a saved search.
+func (c *Client) DeleteSavedSearch(ctx context.Context, ns Namespace, name string) error {
+	headers, err := c.authHeaders(ctx)
+	if err != nil {
+		return err
+	}
+
+	searchURL := fmt.Sprintf("%s%s/saved/searches/%s", c.BaseURL, ns.path(), url.PathEscape(name))
+	resp, err := c.doRequest(ctx, "DELETE", searchURL, headers, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("delete saved search failed: %s", resp.Status)
+	}
+	return nil
+}
+
+// ListSavedSearches lists every saved search visible in the given
+// namespace.
+func (c *Client) ListSavedSearches(ctx context.Context, ns Namespace) ([]SavedSearchSpec, error) {
+	headers, err := c.authHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	searchesURL := c.BaseURL + ns.path() + "/saved/searches?output_mode=json&count=0"
+	resp, err := c.doRequest(ctx, "GET", searchesURL, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("list saved searches failed: %s", resp.Status)
+	}
+
+	var parsed struct {
+		Entry []savedSearchEntry `json:"entry"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse saved searches: %w", err)
+	}
+
+	specs := make([]SavedSearchSpec, len(parsed.Entry))
+	for i, entry := range parsed.Entry {
+		specs[i] = entry.toSpec()
+	}
+	return specs, nil
+}
+
+// DispatchSavedSearch dispatches a saved search as a search job, passing
+// args as dispatch-time SPL arguments (e.g. "args.foo"), and returns a
+// handle to the resulting job, whose SID field holds the job's SID.
+func (c *Client) DispatchSavedSearch(ctx context.Context, ns Namespace, name string, args map[string]string) (*SearchJob, error) {
+	headers, err := c.authHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	headers["Content-Type"] = "application/x-www-form-urlencoded"
+
+	values := url.Values{}
+	for k, v := range args {
+		values.Set(k, v)
+	}
+	body := values.Encode()
+
+	dispatchURL := fmt.Sprintf("%s%s/saved/searches/%s/dispatch?output_mode=json", c.BaseURL, ns.path(), url.PathEscape(name))
+	resp, err := c.doRequest(ctx, "POST", dispatchURL, headers, func() io.Reader { return strings.NewReader(body) })
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("dispatch saved search failed: %s - %s", resp.Status, string(respBody))
+	}
+
+	var parsed struct {
+		SID string `json:"sid"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse dispatch response: %w", err)
+	}
+	if parsed.SID == "" {
+		return nil, fmt.Errorf("dispatch saved search response did not contain a sid")
+	}
+	return &SearchJob{SID: parsed.SID, client: c}, nil
+}
+
+// AlertHistoryEntry is a single firing of a saved search's alert, as
+// reported by GET .../saved/searches/{name}/history.
+type AlertHistoryEntry struct {
+	SID       string
+	Triggered bool
+}
+
+// GetAlertHistory lists the jobs dispatched by a saved search's alert
+// schedule, most recent first.
+func (c *Client) GetAlertHistory(ctx context.Context, ns Namespace, name string) ([]AlertHistoryEntry, error) {
+	headers, err := c.authHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	historyURL := fmt.Sprintf("%s%s/saved/searches/%s/history?output_mode=json", c.BaseURL, ns.path(), url.PathEscape(name))
+	resp, err := c.doRequest(ctx, "GET", historyURL, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get alert history failed: %s", resp.Status)
+	}
+
+	var parsed struct {
+		Entry []struct {
+			Name    string `json:"name"`
+			Content struct {
+				TriggeredAlert bool `json:"triggered_alert"`
+			} `json:"content"`
+		} `json:"entry"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse alert history: %w", err)
+	}
+
+	history := make([]AlertHistoryEntry, len(parsed.Entry))
+	for i, entry := range parsed.Entry {
+		history[i] = AlertHistoryEntry{SID: entry.Name, Triggered: entry.Content.TriggeredAlert}
+	}
+	return history, nil
+}
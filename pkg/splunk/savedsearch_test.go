@@ -0,0 +1,194 @@
+package splunk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func testClientAgainst(server *httptest.Server) *Client {
+	return &Client{BaseURL: server.URL, Token: "test-token", HTTPClient: server.Client()}
+}
+
+func TestCreateSavedSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/servicesNS/-/-/saved/searches" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "name=my-search") {
+			t.Errorf("body missing name: %s", body)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	c := testClientAgainst(server)
+	err := c.CreateSavedSearch(context.Background(), Namespace{}, SavedSearchSpec{Name: "my-search", Search: "search index=main"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateSavedSearch_NonOKStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, "bad spec")
+	}))
+	defer server.Close()
+
+	c := testClientAgainst(server)
+	err := c.CreateSavedSearch(context.Background(), Namespace{}, SavedSearchSpec{Name: "my-search"})
+	if err == nil || !strings.Contains(err.Error(), "create saved search failed") {
+		t.Fatalf("expected create saved search error, got %v", err)
+	}
+}
+
+func TestGetSavedSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/-/-/saved/searches/my-search" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		io.WriteString(w, `{"entry":[{"name":"my-search","content":{"search":"search index=main","cron_schedule":"*/5 * * * *"}}]}`)
+	}))
+	defer server.Close()
+
+	c := testClientAgainst(server)
+	spec, err := c.GetSavedSearch(context.Background(), Namespace{}, "my-search")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spec.Name != "my-search" || spec.Search != "search index=main" || spec.CronSchedule != "*/5 * * * *" {
+		t.Errorf("unexpected spec: %+v", spec)
+	}
+}
+
+func TestGetSavedSearch_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"entry":[]}`)
+	}))
+	defer server.Close()
+
+	c := testClientAgainst(server)
+	_, err := c.GetSavedSearch(context.Background(), Namespace{}, "missing")
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestUpdateSavedSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/-/-/saved/searches/my-search" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "name=") {
+			t.Errorf("expected name to be omitted from an update body: %s", body)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := testClientAgainst(server)
+	err := c.UpdateSavedSearch(context.Background(), Namespace{}, "my-search", SavedSearchSpec{Search: "search index=other"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDeleteSavedSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("expected DELETE, got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := testClientAgainst(server)
+	if err := c.DeleteSavedSearch(context.Background(), Namespace{}, "my-search"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestListSavedSearches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"entry":[{"name":"a","content":{"search":"search 1"}},{"name":"b","content":{"search":"search 2"}}]}`)
+	}))
+	defer server.Close()
+
+	c := testClientAgainst(server)
+	specs, err := c.ListSavedSearches(context.Background(), Namespace{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(specs) != 2 || specs[0].Name != "a" || specs[1].Name != "b" {
+		t.Errorf("unexpected specs: %+v", specs)
+	}
+}
+
+func TestDispatchSavedSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/-/-/saved/searches/my-search/dispatch" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("output_mode") != "json" {
+			t.Errorf("expected output_mode=json, got %q", r.URL.RawQuery)
+		}
+		io.WriteString(w, `{"sid":"12345"}`)
+	}))
+	defer server.Close()
+
+	c := testClientAgainst(server)
+	job, err := c.DispatchSavedSearch(context.Background(), Namespace{}, "my-search", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.SID != "12345" {
+		t.Errorf("expected sid 12345, got %q", job.SID)
+	}
+}
+
+func TestDispatchSavedSearch_MissingSID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{}`)
+	}))
+	defer server.Close()
+
+	c := testClientAgainst(server)
+	_, err := c.DispatchSavedSearch(context.Background(), Namespace{}, "my-search", nil)
+	if err == nil || !strings.Contains(err.Error(), "did not contain a sid") {
+		t.Fatalf("expected missing sid error, got %v", err)
+	}
+}
+
+func TestGetAlertHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"entry":[{"name":"12345","content":{"triggered_alert":true}}]}`)
+	}))
+	defer server.Close()
+
+	c := testClientAgainst(server)
+	history, err := c.GetAlertHistory(context.Background(), Namespace{}, "my-search")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 1 || history[0].SID != "12345" || !history[0].Triggered {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
+
+func TestNamespace_PathDefaultsToWildcard(t *testing.T) {
+	if got := (Namespace{}).path(); got != "/servicesNS/-/-" {
+		t.Errorf("expected wildcard namespace path, got %q", got)
+	}
+	if got := (Namespace{User: "admin", App: "search"}).path(); got != "/servicesNS/admin/search" {
+		t.Errorf("unexpected namespace path: %q", got)
+	}
+}
@@ -1,7 +1,7 @@
 package splunk
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,32 +16,83 @@ type Client struct {
 	Password   string
 	Token      string
 	HTTPClient *http.Client
+
+	// MaxBatchBytes caps the size of a single HEC batch request body. A
+	// []Event passed to SendEvents is split across as many batches as
+	// needed to stay under this limit. Defaults to defaultMaxBatchBytes
+	// when zero.
+	MaxBatchBytes int
+	// MaxBatchEvents caps the number of events in a single HEC batch
+	// request. Defaults to defaultMaxBatchEvents when zero.
+	MaxBatchEvents int
+	// UseAck enables HEC indexer acknowledgement: each batch is sent with
+	// the X-Splunk-Request-Channel header and its ackId is returned so
+	// callers can confirm durability with WaitForAcks.
+	UseAck bool
+
+	// RetryHook, if set, is called after every attempt doRequest makes,
+	// with or without a retryPolicy. Install both via WithRetry/WithRetryHook.
+	RetryHook RetryHook
+
+	// requestChannel is the UUID sent as X-Splunk-Request-Channel when
+	// UseAck is enabled. It is generated once per client so Splunk can
+	// track acknowledgement state across the channel's lifetime.
+	requestChannel string
+	// retryPolicy, when non-nil, is applied to every outbound request by
+	// doRequest. Install it with WithRetry.
+	retryPolicy *RetryPolicy
+	// auth, when set via WithAuth, takes over from the Username/Password/
+	// Token fields for producing the Authorization (or cookie) header on
+	// every request.
+	auth Authenticator
 }
 
-// Event represents a single event to be sent to Splunk.
+// Event represents a single event to be sent to Splunk. Time is the
+// event's epoch timestamp, in seconds; leave it zero to let Splunk
+// assign the time of indexing rather than mis-timestamping the event at
+// the epoch.
 type Event struct {
-	Time  int64          `json:"time"`
+	Time  int64          `json:"time,omitempty"`
 	Event map[string]any `json:"event"`
 }
 
 // NewClient creates a new Splunk client.
 // It requires either a username and password or a token for authentication.
 // If both are provided, the token will take precedence.
-func NewClient(baseURL string, username, password, token string) (*Client, error) {
+//
+// opts configures transport-level concerns such as TLS trust and mutual
+// TLS via WithCACertFile, WithCACertPEM, WithInsecureSkipVerify,
+// WithClientCertificate, WithTimeout, and WithTransport.
+func NewClient(baseURL string, username, password, token string, opts ...Option) (*Client, error) {
 	if token == "" && (username == "" || password == "") {
 		return nil, fmt.Errorf("either a token or a username and password must be provided")
 	}
 
-	return &Client{
+	c := &Client{
 		BaseURL:  baseURL,
 		Username: username,
 		Token:    token,
 		HTTPClient: &http.Client{
 			Timeout: 30 * time.Second, // Set a timeout to avoid unbounded request durations
 		},
-	}, nil
+		requestChannel: newRequestChannel(),
+		Password:       password,
+	}
+	c.transport()
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
 }
 
+// searchPollInterval is how often Search polls job status while waiting
+// for a oneshot-style call to complete.
+const searchPollInterval = 500 * time.Millisecond
+
 // Search executes a search query against the Splunk service using the provided query string and parameters.
 // Parameters:
 //   - query: The Splunk search query to execute.
@@ -50,16 +101,16 @@ func NewClient(baseURL string, username, password, token string) (*Client, error
 //
 // Supported optional parameters: exec_mode (default "normal"), earliest_time, latest_time.
 //
+// Search is a thin wrapper around CreateJob, Job.Wait, and Job.Results kept
+// for backward compatibility; new code that needs cancellation, pagination,
+// or access to job status should use CreateJob directly.
+//
 // Returns:
-//   - map[string]any: The parsed search results.
+//   - map[string]any: The parsed search results, with a "results" key
+//     holding the rows and a "fields" key holding the field names.
 //   - error: An error if the search request fails or the response cannot be parsed.
 func (c *Client) Search(query string, options ...string) (map[string]any, error) {
-	// Set defaults
-	execMode := "normal"
-	earliestTime := ""
-	latestTime := ""
-
-	// Parse options
+	opts := JobOptions{ExecMode: "normal"}
 	for _, opt := range options {
 		parts := strings.SplitN(opt, "=", 2)
 		if len(parts) != 2 {
@@ -68,93 +119,64 @@ func (c *Client) Search(query string, options ...string) (map[string]any, error)
 		key, value := parts[0], parts[1]
 		switch key {
 		case "exec_mode":
-			execMode = value
+			opts.ExecMode = value
 		case "earliest_time":
-			earliestTime = value
+			opts.EarliestTime = value
 		case "latest_time":
-			latestTime = value
+			opts.LatestTime = value
 		}
 	}
 
-	searchURL := c.BaseURL + "/services/search/jobs"
-	params := []string{
-		fmt.Sprintf("search=%s", query),
-		fmt.Sprintf("exec_mode=%s", execMode),
-		"output_mode=json",
-	}
-	if earliestTime != "" {
-		params = append(params, fmt.Sprintf("earliest_time=%s", earliestTime))
-	}
-	if latestTime != "" {
-		params = append(params, fmt.Sprintf("latest_time=%s", latestTime))
-	}
-	reqBody := strings.Join(params, "&")
-
-	req, err := http.NewRequest("POST", searchURL, strings.NewReader(reqBody))
+	ctx := context.Background()
+	job, err := c.CreateJob(ctx, query, opts)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	if err := c.setAuthHeader(req); err != nil {
+	if err := job.Wait(ctx, searchPollInterval); err != nil {
 		return nil, err
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	results, err := job.Results(ctx, 0, 0)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("search request failed: %s", resp.Status)
+	rows := make([]any, len(results.Rows))
+	for i, row := range results.Rows {
+		rows[i] = map[string]any(row)
 	}
-
-	return parseSplunkSearchResults(resp.Body)
+	return map[string]any{
+		"results": rows,
+		"fields":  results.Fields,
+	}, nil
 }
 
-// SendEvents sends events to a Splunk index using the HTTP Event Collector (HEC) API.
-func (c *Client) SendEvents(indexName string, events []Event) error {
-	if c.Token == "" {
-		return fmt.Errorf("HEC requires a token for authentication")
+// SearchResults runs query to completion like Search, but returns a
+// ResultReader streaming the job's results instead of a buffered
+// map[string]any. Use this for large result sets, typed decoding via
+// ResultReader.Decode, or output modes other than JSON.
+func (c *Client) SearchResults(ctx context.Context, query string, opts JobOptions) (*ResultReader, error) {
+	job, err := c.CreateJob(ctx, query, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	hecURL := strings.TrimRight(c.BaseURL, "/") + "/services/collector/event"
-	for _, event := range events {
-		payload := map[string]any{
-			"index": indexName,
-			"time":  event.Time,
-			"event": event.Event,
-		}
-		body, err := json.Marshal(payload)
-		if err != nil {
-			return fmt.Errorf("failed to marshal event: %w", err)
-		}
-
-		req, err := http.NewRequest("POST", hecURL, strings.NewReader(string(body)))
-		if err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
-		}
-		req.Header.Set("Authorization", "Splunk "+c.Token)
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := c.HTTPClient.Do(req)
-		if err != nil {
-			return fmt.Errorf("failed to send event: %w", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			respBody, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("failed to send event: %s - %s", resp.Status, string(respBody))
-		}
+	if err := job.Wait(ctx, searchPollInterval); err != nil {
+		return nil, err
 	}
-	return nil
 
+	return job.ResultReader(ctx, 0, 0, "json")
 }
 
-// setAuthHeader sets the appropriate authentication header for the request.
-func (c *Client) setAuthHeader(req *http.Request) error {
+// setAuthHeader sets the appropriate authentication header for the
+// request. If an Authenticator has been installed with WithAuth, it takes
+// over entirely; otherwise this falls back to the Username/Password/Token
+// fields NewClient was constructed with.
+func (c *Client) setAuthHeader(ctx context.Context, req *http.Request) error {
+	if c.auth != nil {
+		return c.auth.Authenticate(ctx, c, req)
+	}
 	if c.Token != "" {
 		req.Header.Set("Authorization", "Bearer "+c.Token)
 		return nil
@@ -166,12 +188,35 @@ func (c *Client) setAuthHeader(req *http.Request) error {
 	return fmt.Errorf("no authentication credentials provided")
 }
 
-// parseSplunkSearchResults parses the Splunk search results from the response body.
-func parseSplunkSearchResults(body io.Reader) (map[string]any, error) {
-	var result map[string]any
-	decoder := json.NewDecoder(body)
-	if err := decoder.Decode(&result); err != nil {
+// authHeaders returns the header set doRequest should attach to every
+// retry attempt, including whatever headers setAuthHeader produces (an
+// Authorization header, or one or more Cookie headers for CookieAuth).
+func (c *Client) authHeaders(ctx context.Context) (map[string]string, error) {
+	req := &http.Request{Header: http.Header{}}
+	if err := c.setAuthHeader(ctx, req); err != nil {
 		return nil, err
 	}
-	return result, nil
+	headers := make(map[string]string, len(req.Header))
+	for k := range req.Header {
+		headers[k] = req.Header.Get(k)
+	}
+	return headers, nil
+}
+
+// AuthHeaders returns the header set (Authorization, Cookie, etc.) that
+// Do would attach to an outbound request. It's exported for subpackages
+// such as kvstore that issue their own requests against endpoints
+// splunk.Client doesn't wrap directly, so they can authenticate the same
+// way Search and the job-lifecycle methods do.
+func (c *Client) AuthHeaders(ctx context.Context) (map[string]string, error) {
+	return c.authHeaders(ctx)
+}
+
+// Do issues an HTTP request through the same retry and 401
+// re-authentication machinery as the Client's own methods. It's exported
+// for subpackages such as kvstore; most callers should prefer Search,
+// CreateJob, or SendEvents instead. bodyFn, if non-nil, is invoked fresh
+// for every retry attempt.
+func (c *Client) Do(ctx context.Context, method, url string, headers map[string]string, bodyFn func() io.Reader) (*http.Response, error) {
+	return c.doRequest(ctx, method, url, headers, bodyFn)
 }